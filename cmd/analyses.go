@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"gamedl/internal/registry"
+
+	"github.com/spf13/cobra"
+)
+
+var analysesCmd = &cobra.Command{
+	Use:   "analyses",
+	Short: "List the supported analyses of each competition",
+	Long: `List the analyses the analyze command can run for each competition, with the
+directory of the input directory their games are read from.`,
+	Run: runAnalyses,
+}
+
+func init() {
+	rootCmd.AddCommand(analysesCmd)
+}
+
+func runAnalyses(cmd *cobra.Command, args []string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPETITION\tANALYSIS\tDIRECTORY\tDESCRIPTION")
+	for _, analysis := range registry.Analyses() {
+		directory := analysis.GamesDirectory()
+		if !analysis.RequiresYears {
+			directory = "(whole input directory)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", analysis.Competition, analysis.Name, directory, analysis.Description)
+	}
+	w.Flush()
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"gamedl/internal/analyze"
+	"gamedl/internal/common"
+	"gamedl/internal/registry"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -27,11 +29,12 @@ Configuration precedence (highest to lowest):
 func init() {
 	rootCmd.AddCommand(analyzeCmd)
 
-	analyzeCmd.Flags().StringP("competition", "c", "", "Competition to analyze (values allowed: 'nfl', 'ncaab', 'ncaaf' or 'nba') (required)")
-	analyzeCmd.Flags().StringP("analysis", "a", "", "Analysis type to perform (e.g., 'action-types', 'review-types', 'lane-violations') (required)")
+	analyzeCmd.Flags().StringP("competition", "c", "", "Competition to analyze (values allowed: "+quoteValues(registry.AnalysisCompetitionNames())+") (required)")
+	analyzeCmd.Flags().StringP("analysis", "a", "", "Analysis type to perform (values allowed: "+quoteValues(registry.AnalysisNames())+", see 'gamedl analyses' for the ones of each competition) (required)")
 	analyzeCmd.Flags().StringP("input-dir", "i", "downloaded_games", "Directory containing downloaded game files")
 	analyzeCmd.Flags().StringP("output", "o", "analysis_results", "Output directory for analysis results")
 	analyzeCmd.Flags().StringSliceP("seasons", "s", nil, "Seasons to include in analysis, comma-separated. e.g '2023,2024' (default: all seasons available in the input directory)")
+	analyzeCmd.Flags().StringSliceP("season-types", "", []string{common.RegularSeason}, "Season types to include in analysis, comma-separated. e.g 'REG,PST'")
 
 	// Note: We handle required validation in RunE since we use viper for config precedence
 
@@ -40,6 +43,7 @@ func init() {
 	viper.BindPFlag("analyze.input-dir", analyzeCmd.Flags().Lookup("input-dir"))
 	viper.BindPFlag("analyze.output", analyzeCmd.Flags().Lookup("output"))
 	viper.BindPFlag("analyze.seasons", analyzeCmd.Flags().Lookup("seasons"))
+	viper.BindPFlag("analyze.season-types", analyzeCmd.Flags().Lookup("season-types"))
 
 	// Also bind environment variables directly
 	viper.BindEnv("analyze.competition", "GAMEDL_ANALYZE_COMPETITION")
@@ -47,6 +51,7 @@ func init() {
 	viper.BindEnv("analyze.input-dir", "GAMEDL_ANALYZE_INPUT_DIR")
 	viper.BindEnv("analyze.output", "GAMEDL_ANALYZE_OUTPUT")
 	viper.BindEnv("analyze.seasons", "GAMEDL_ANALYZE_SEASONS")
+	viper.BindEnv("analyze.season-types", "GAMEDL_ANALYZE_SEASON_TYPES")
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) error {
@@ -55,6 +60,10 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	inputDir := viper.GetString("analyze.input-dir")
 	outputDir := viper.GetString("analyze.output")
 	seasonsStr := viper.GetStringSlice("analyze.seasons")
+	seasonTypes, err := parseSeasonTypes(viper.GetStringSlice("analyze.season-types"))
+	if err != nil {
+		return err
+	}
 
 	if competition == "" {
 		return fmt.Errorf("competition is required")
@@ -64,10 +73,8 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("analysis type is required")
 	}
 
-	validCompetitions := []string{"nfl", "ncaab", "ncaaf", "nba"}
-
-	if !contains(validCompetitions, competition) {
-		return fmt.Errorf("invalid competition %s. Valid options: %s", competition, strings.Join(validCompetitions, ", "))
+	if _, err := registry.LookupAnalysis(competition, analysisType); err != nil {
+		return err
 	}
 
 	var seasons []int
@@ -90,6 +97,7 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Println("Seasons: all available")
 	}
+	fmt.Printf("Season types: %v\n", seasonTypes)
 
 	config := analyze.Config{
 		Competition:  competition,
@@ -97,6 +105,7 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		InputDir:     inputDir,
 		OutputDir:    outputDir,
 		Seasons:      seasons,
+		SeasonTypes:  seasonTypes,
 	}
 
 	if err := analyze.Run(config); err != nil {
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"gamedl/internal/diff"
+	"gamedl/internal/registry"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <game-id> | diff <old-file> <new-file>",
+	Short: "Report what a provider changed between versions of a game payload",
+	Long: `Compare successive versions of a game payload and report the events inserted, removed,
+listed as deleted, re-sequenced or corrected by the provider. Events are matched by id.
+
+With a game id, the snapshots of the game saved by the watch command are compared in order,
+followed by its game file. With two files, only those are compared.
+`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringP("competition", "c", "", "Competition of the game (values allowed: "+quoteValues(registry.CompetitionNames())+") (required)")
+	diffCmd.Flags().StringP("provider", "p", "", "Data provider of the game (values allowed: "+quoteValues(registry.ProviderNames())+") (required)")
+	diffCmd.Flags().StringP("input-dir", "i", "downloaded_games", "Directory containing downloaded game files")
+	diffCmd.Flags().StringP("format", "f", "markdown", "Report format (values allowed: 'markdown' or 'json')")
+	diffCmd.Flags().StringP("output", "o", "", "File to write the report to (default: stdout)")
+
+	viper.BindPFlag("diff.competition", diffCmd.Flags().Lookup("competition"))
+	viper.BindPFlag("diff.provider", diffCmd.Flags().Lookup("provider"))
+	viper.BindPFlag("diff.input-dir", diffCmd.Flags().Lookup("input-dir"))
+	viper.BindPFlag("diff.format", diffCmd.Flags().Lookup("format"))
+	viper.BindPFlag("diff.output", diffCmd.Flags().Lookup("output"))
+
+	viper.BindEnv("diff.competition", "GAMEDL_DIFF_COMPETITION")
+	viper.BindEnv("diff.provider", "GAMEDL_DIFF_PROVIDER")
+	viper.BindEnv("diff.input-dir", "GAMEDL_DIFF_INPUT_DIR")
+	viper.BindEnv("diff.format", "GAMEDL_DIFF_FORMAT")
+	viper.BindEnv("diff.output", "GAMEDL_DIFF_OUTPUT")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	config := diff.Config{
+		Competition: viper.GetString("diff.competition"),
+		Provider:    viper.GetString("diff.provider"),
+		InputDir:    viper.GetString("diff.input-dir"),
+		Format:      viper.GetString("diff.format"),
+		Output:      viper.GetString("diff.output"),
+	}
+
+	if config.Competition == "" {
+		return fmt.Errorf("competition is required")
+	}
+
+	if config.Provider == "" {
+		return fmt.Errorf("provider is required")
+	}
+
+	if len(args) == 1 {
+		config.GameID = args[0]
+	} else {
+		config.Files = args
+	}
+
+	if err := diff.Run(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Diff failed: %v\n", err)
+		return err
+	}
+
+	return nil
+}
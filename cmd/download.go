@@ -5,8 +5,16 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"gamedl/internal/common"
 	"gamedl/internal/download"
+	"gamedl/internal/download/engine"
+	"gamedl/internal/registry"
+	"gamedl/lib/web/clients/ratelimit"
+	"gamedl/lib/web/clients/retry"
+	"gamedl/lib/web/clients/sportsradar"
+	"gamedl/lib/web/clients/usage"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -24,26 +32,118 @@ Supports SportRadar and BetGenius providers for different competitions.
 func init() {
 	rootCmd.AddCommand(downloadCmd)
 
-	downloadCmd.Flags().StringP("competition", "c", "", "Competition to download (values allowed: 'nfl', 'ncaab' or ncaaf) (required)")
-	downloadCmd.Flags().StringP("provider", "p", "", "Data provider (values allowed: 'sportradar', 'sr', 'betgenius', 'genius' or 'bg') (required)")
+	defaultRetry := retry.DefaultPolicy()
+
+	downloadCmd.Flags().StringP("competition", "c", "", "Competition to download (values allowed: "+quoteValues(registry.CompetitionNames())+") (required)")
+	downloadCmd.Flags().StringP("provider", "p", "", "Data provider (values allowed: "+quoteValues(registry.ProviderNames())+") (required)")
 	downloadCmd.Flags().StringSliceP("seasons", "s", nil, "Seasons to download, comma-separated. e.g '2023,2024' (default: all seasons available in the provider)")
+	downloadCmd.Flags().StringSliceP("season-types", "", []string{common.RegularSeason}, "Season types to download, comma-separated. e.g 'REG,PST' (values allowed: "+strings.Join(validSeasonTypes, ", ")+"). Only SportRadar supports season types other than REG")
+	downloadCmd.Flags().StringP("from", "", "", "Only download games scheduled on or after this date, in YYYY-MM-DD format")
+	downloadCmd.Flags().StringP("to", "", "", "Only download games scheduled on or before this date, in YYYY-MM-DD format")
+	downloadCmd.Flags().StringSliceP("team", "", nil, "Only download games of these teams, comma-separated. Teams can be given by alias, name, SportRadar id or BetGenius competitor id")
+	downloadCmd.Flags().StringSliceP("status", "", nil, "Game statuses to download, comma-separated. e.g 'closed,complete' (default: 'closed' for SportRadar, 'scheduled' for BetGenius)")
+	downloadCmd.Flags().StringSliceP("game-ids", "", nil, "Download only these games, comma-separated. Each game is saved into the directory of its season")
+	downloadCmd.Flags().StringP("game-ids-file", "", "", "File listing games to download, one id per line (lines starting with # are ignored), or a plan written with --plan-file")
 	downloadCmd.Flags().IntP("concurrency", "", 10, "Number of concurrent downloads")
 	downloadCmd.Flags().StringP("output-dir", "o", "downloaded_games", "Directory to store downloaded game files")
+	downloadCmd.Flags().StringP("compression", "", common.NoCompression, "Compression of saved game files (values allowed: 'none' or 'gzip')")
+	downloadCmd.Flags().BoolP("compact", "", false, "Save game files as received from the provider instead of indenting them")
+	downloadCmd.Flags().BoolP("incremental", "", false, "Skip games whose saved payload is complete, final and unchanged in the schedule since the last run")
+	downloadCmd.Flags().BoolP("dry-run", "", false, "Only fetch the seasons and schedules and print what the download would do")
+	downloadCmd.Flags().StringP("plan-file", "", "", "With --dry-run, also write the plan as JSON to this file. It can be given back to --game-ids-file")
+	downloadCmd.Flags().BoolP("verbose", "v", false, "Print a line per downloaded game instead of the progress display")
+	downloadCmd.Flags().StringP("report", "", "", "File the JSON report of the run is written to (default: <output-dir>/reports/<directory>-<start time>.json)")
+	downloadCmd.Flags().StringP("retry-failed", "", "", "Report of a previous run whose failed games are downloaded again, with the competition, provider and seasons of the report unless given")
+	downloadCmd.Flags().IntP("max-calls", "", 0, "Maximum number of API calls of the run, retries included. Games left are written to a remaining file (default: unlimited)")
+	downloadCmd.Flags().StringP("record", "", "", "Directory every request of the run and its response are recorded to, secrets redacted, to be replayed with --replay")
+	downloadCmd.Flags().StringP("replay", "", "", "Directory of a recording whose responses are served instead of sending the requests. Requests not recorded fail")
+	downloadCmd.Flags().IntP("max-attempts", "", defaultRetry.MaxAttempts, "Maximum number of attempts per request, including the first one")
+	downloadCmd.Flags().IntSliceP("retry-statuses", "", defaultRetry.RetryableStatuses, "HTTP status codes that are retried, comma-separated")
+	downloadCmd.Flags().DurationP("retry-base-delay", "", defaultRetry.BaseDelay, "Delay before the first retry, doubled on every following retry (a Retry-After header takes precedence)")
+	downloadCmd.Flags().DurationP("retry-max-delay", "", defaultRetry.MaxDelay, "Maximum delay between retries")
+	downloadCmd.Flags().Float64P("sr-schedule-rps", "", 0, "Maximum SportRadar season and schedule requests per second (default: unlimited)")
+	downloadCmd.Flags().Float64P("sr-pbp-rps", "", 0, "Maximum SportRadar play by play requests per second (default: unlimited)")
+	downloadCmd.Flags().StringP("sr-key-rotation", "", string(sportsradar.RoundRobin), "How SportRadar requests pick among the api keys of a competition (values allowed: "+quoteValues(rotationNames())+"). Exhausted keys are skipped either way")
+	downloadCmd.Flags().StringP("sr-access-level", "", "", "Access level of the SportRadar api keys (values allowed: "+quoteValues(accessLevelNames())+") (default: sportradar.api.<competition>.access-level, or 'trial')")
+	downloadCmd.Flags().StringP("sr-api-version", "", "", "Version of the SportRadar API, e.g. 'v8' (default: sportradar.api.<competition>.version, or the latest version supported)")
+	downloadCmd.Flags().StringP("sr-locale", "", "", "Language of the SportRadar payload descriptions, e.g. 'es' (default: sportradar.api.<competition>.locale, or 'en')")
+	downloadCmd.Flags().Float64P("bg-schedule-rps", "", 0, "Maximum BetGenius season and fixture requests per second (default: unlimited)")
+	downloadCmd.Flags().Float64P("bg-pbp-rps", "", 0, "Maximum BetGenius play by play requests per second (default: unlimited)")
 
 	// Note: We handle required validation in RunE since we use viper for config precedence
 
 	viper.BindPFlag("download.competition", downloadCmd.Flags().Lookup("competition"))
 	viper.BindPFlag("download.provider", downloadCmd.Flags().Lookup("provider"))
 	viper.BindPFlag("download.seasons", downloadCmd.Flags().Lookup("seasons"))
+	viper.BindPFlag("download.season-types", downloadCmd.Flags().Lookup("season-types"))
+	viper.BindPFlag("download.from", downloadCmd.Flags().Lookup("from"))
+	viper.BindPFlag("download.to", downloadCmd.Flags().Lookup("to"))
+	viper.BindPFlag("download.team", downloadCmd.Flags().Lookup("team"))
+	viper.BindPFlag("download.status", downloadCmd.Flags().Lookup("status"))
+	viper.BindPFlag("download.game-ids", downloadCmd.Flags().Lookup("game-ids"))
+	viper.BindPFlag("download.game-ids-file", downloadCmd.Flags().Lookup("game-ids-file"))
 	viper.BindPFlag("download.concurrency", downloadCmd.Flags().Lookup("concurrency"))
 	viper.BindPFlag("download.output-dir", downloadCmd.Flags().Lookup("output-dir"))
+	viper.BindPFlag("download.compression", downloadCmd.Flags().Lookup("compression"))
+	viper.BindPFlag("download.compact", downloadCmd.Flags().Lookup("compact"))
+	viper.BindPFlag("download.incremental", downloadCmd.Flags().Lookup("incremental"))
+	viper.BindPFlag("download.dry-run", downloadCmd.Flags().Lookup("dry-run"))
+	viper.BindPFlag("download.plan-file", downloadCmd.Flags().Lookup("plan-file"))
+	viper.BindPFlag("download.verbose", downloadCmd.Flags().Lookup("verbose"))
+	viper.BindPFlag("download.report", downloadCmd.Flags().Lookup("report"))
+	viper.BindPFlag("download.retry-failed", downloadCmd.Flags().Lookup("retry-failed"))
+	viper.BindPFlag("download.max-calls", downloadCmd.Flags().Lookup("max-calls"))
+	viper.BindPFlag("download.record", downloadCmd.Flags().Lookup("record"))
+	viper.BindPFlag("download.replay", downloadCmd.Flags().Lookup("replay"))
+	viper.BindPFlag("download.max-attempts", downloadCmd.Flags().Lookup("max-attempts"))
+	viper.BindPFlag("download.retry-statuses", downloadCmd.Flags().Lookup("retry-statuses"))
+	viper.BindPFlag("download.retry-base-delay", downloadCmd.Flags().Lookup("retry-base-delay"))
+	viper.BindPFlag("download.retry-max-delay", downloadCmd.Flags().Lookup("retry-max-delay"))
+	viper.BindPFlag("download.rate-limits.sportradar.schedule", downloadCmd.Flags().Lookup("sr-schedule-rps"))
+	viper.BindPFlag("download.rate-limits.sportradar.pbp", downloadCmd.Flags().Lookup("sr-pbp-rps"))
+	viper.BindPFlag("download.sr-key-rotation", downloadCmd.Flags().Lookup("sr-key-rotation"))
+	viper.BindPFlag("download.sr-access-level", downloadCmd.Flags().Lookup("sr-access-level"))
+	viper.BindPFlag("download.sr-api-version", downloadCmd.Flags().Lookup("sr-api-version"))
+	viper.BindPFlag("download.sr-locale", downloadCmd.Flags().Lookup("sr-locale"))
+	viper.BindPFlag("download.rate-limits.betgenius.schedule", downloadCmd.Flags().Lookup("bg-schedule-rps"))
+	viper.BindPFlag("download.rate-limits.betgenius.pbp", downloadCmd.Flags().Lookup("bg-pbp-rps"))
 
 	// Also bind environment variables directly
 	viper.BindEnv("download.competition", "GAMEDL_DOWNLOAD_COMPETITION")
 	viper.BindEnv("download.provider", "GAMEDL_DOWNLOAD_PROVIDER")
 	viper.BindEnv("download.seasons", "GAMEDL_DOWNLOAD_SEASONS")
+	viper.BindEnv("download.season-types", "GAMEDL_DOWNLOAD_SEASON_TYPES")
+	viper.BindEnv("download.from", "GAMEDL_DOWNLOAD_FROM")
+	viper.BindEnv("download.to", "GAMEDL_DOWNLOAD_TO")
+	viper.BindEnv("download.team", "GAMEDL_DOWNLOAD_TEAM")
+	viper.BindEnv("download.status", "GAMEDL_DOWNLOAD_STATUS")
+	viper.BindEnv("download.game-ids", "GAMEDL_DOWNLOAD_GAME_IDS")
+	viper.BindEnv("download.game-ids-file", "GAMEDL_DOWNLOAD_GAME_IDS_FILE")
 	viper.BindEnv("download.concurrency", "GAMEDL_DOWNLOAD_CONCURRENCY")
 	viper.BindEnv("download.output-dir", "GAMEDL_DOWNLOAD_OUTPUT_DIR")
+	viper.BindEnv("download.compression", "GAMEDL_DOWNLOAD_COMPRESSION")
+	viper.BindEnv("download.compact", "GAMEDL_DOWNLOAD_COMPACT")
+	viper.BindEnv("download.incremental", "GAMEDL_DOWNLOAD_INCREMENTAL")
+	viper.BindEnv("download.dry-run", "GAMEDL_DOWNLOAD_DRY_RUN")
+	viper.BindEnv("download.plan-file", "GAMEDL_DOWNLOAD_PLAN_FILE")
+	viper.BindEnv("download.verbose", "GAMEDL_DOWNLOAD_VERBOSE")
+	viper.BindEnv("download.report", "GAMEDL_DOWNLOAD_REPORT")
+	viper.BindEnv("download.retry-failed", "GAMEDL_DOWNLOAD_RETRY_FAILED")
+	viper.BindEnv("download.max-calls", "GAMEDL_DOWNLOAD_MAX_CALLS")
+	viper.BindEnv("download.record", "GAMEDL_DOWNLOAD_RECORD")
+	viper.BindEnv("download.replay", "GAMEDL_DOWNLOAD_REPLAY")
+	viper.BindEnv("download.max-attempts", "GAMEDL_DOWNLOAD_MAX_ATTEMPTS")
+	viper.BindEnv("download.retry-statuses", "GAMEDL_DOWNLOAD_RETRY_STATUSES")
+	viper.BindEnv("download.retry-base-delay", "GAMEDL_DOWNLOAD_RETRY_BASE_DELAY")
+	viper.BindEnv("download.retry-max-delay", "GAMEDL_DOWNLOAD_RETRY_MAX_DELAY")
+	viper.BindEnv("download.rate-limits.sportradar.schedule", "GAMEDL_DOWNLOAD_SR_SCHEDULE_RPS")
+	viper.BindEnv("download.rate-limits.sportradar.pbp", "GAMEDL_DOWNLOAD_SR_PBP_RPS")
+	viper.BindEnv("download.sr-key-rotation", "GAMEDL_DOWNLOAD_SR_KEY_ROTATION")
+	viper.BindEnv("download.sr-access-level", "GAMEDL_DOWNLOAD_SR_ACCESS_LEVEL")
+	viper.BindEnv("download.sr-api-version", "GAMEDL_DOWNLOAD_SR_API_VERSION")
+	viper.BindEnv("download.sr-locale", "GAMEDL_DOWNLOAD_SR_LOCALE")
+	viper.BindEnv("download.rate-limits.betgenius.schedule", "GAMEDL_DOWNLOAD_BG_SCHEDULE_RPS")
+	viper.BindEnv("download.rate-limits.betgenius.pbp", "GAMEDL_DOWNLOAD_BG_PBP_RPS")
 }
 
 func runDownload(cmd *cobra.Command, args []string) error {
@@ -51,7 +151,78 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	provider := viper.GetString("download.provider")
 	seasonsStr := viper.GetStringSlice("download.seasons")
 	concurrency := viper.GetInt("download.concurrency")
+	filter, err := parseGameFilter(viper.GetString("download.from"), viper.GetString("download.to"), viper.GetStringSlice("download.team"))
+	if err != nil {
+		return err
+	}
+	seasonTypes, err := parseSeasonTypes(viper.GetStringSlice("download.season-types"))
+	if err != nil {
+		return err
+	}
+	var statuses []string
+	for _, status := range viper.GetStringSlice("download.status") {
+		if status = strings.TrimSpace(status); status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+	gameIDs, err := collectGameIDs(viper.GetStringSlice("download.game-ids"), viper.GetString("download.game-ids-file"))
+	if err != nil {
+		return err
+	}
 	outputDir := viper.GetString("download.output-dir")
+	storage, err := parseStorage(viper.GetString("download.compression"), viper.GetBool("download.compact"))
+	if err != nil {
+		return err
+	}
+	incremental := viper.GetBool("download.incremental")
+	dryRun := viper.GetBool("download.dry-run")
+	planFile := viper.GetString("download.plan-file")
+	verbose := viper.GetBool("download.verbose")
+	reportFile := viper.GetString("download.report")
+	retryFailed := viper.GetString("download.retry-failed")
+	retryPolicy := retryPolicyOf("download")
+	rateLimits := rateLimitsOf("download")
+
+	if retryFailed != "" {
+		report, err := engine.ReadReport(retryFailed)
+		if err != nil {
+			return err
+		}
+		failed := report.FailedGames()
+		if len(failed) == 0 {
+			fmt.Printf("No failed games in %s\n", retryFailed)
+			return nil
+		}
+		// The run is retried as it was reported, unless given otherwise on the command line
+		if !cmd.Flags().Changed("provider") {
+			provider = report.Provider
+		}
+		if !cmd.Flags().Changed("competition") {
+			p, err := registry.LookupProvider(report.Provider)
+			if err != nil {
+				return err
+			}
+			c, err := p.CompetitionByDirectory(report.Directory)
+			if err != nil {
+				return err
+			}
+			competition = c.Name
+		}
+		reportSeasons := !cmd.Flags().Changed("seasons")
+		if reportSeasons {
+			seasonsStr = nil
+		}
+		for _, game := range failed {
+			if !contains(gameIDs, game.ID) {
+				gameIDs = append(gameIDs, game.ID)
+			}
+			year := strconv.Itoa(game.Year)
+			if reportSeasons && game.Year != 0 && !contains(seasonsStr, year) {
+				seasonsStr = append(seasonsStr, year)
+			}
+		}
+		fmt.Printf("Retrying %d failed games of %s\n", len(failed), retryFailed)
+	}
 
 	if competition == "" {
 		return fmt.Errorf("competition is required")
@@ -61,15 +232,43 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("provider is required")
 	}
 
-	validCompetitions := []string{"nfl", "ncaab", "ncaaf", "nba"}
-	validProviders := []string{"sportradar", "sr", "betgenius", "genius", "bg"}
+	if _, _, err := registry.LookupCompetition(provider, competition); err != nil {
+		return err
+	}
 
-	if !contains(validCompetitions, competition) {
-		return fmt.Errorf("invalid competition %s. Valid options: %s", competition, strings.Join(validCompetitions, ", "))
+	if planFile != "" && !dryRun {
+		return fmt.Errorf("plan file requires a dry run")
 	}
 
-	if !contains(validProviders, provider) {
-		return fmt.Errorf("invalid provider %s. Valid options: %s", provider, strings.Join(validProviders, ", "))
+	if retryPolicy.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1")
+	}
+
+	keyRotation, err := parseKeyRotation(viper.GetString("download.sr-key-rotation"))
+	if err != nil {
+		return err
+	}
+
+	httpOpts, err := httpOptions()
+	if err != nil {
+		return err
+	}
+
+	replayer, err := useCassette(&httpOpts, viper.GetString("download.record"), viper.GetString("download.replay"))
+	if err != nil {
+		return err
+	}
+
+	maxCalls := viper.GetInt("download.max-calls")
+	ledger, err := loadUsageLedger(maxCalls)
+	if err != nil {
+		return err
+	}
+	// A replay sends nothing, so it isn't rate limited nor counted
+	var recorder usage.Recorder = ledger
+	if replayer != nil {
+		rateLimits = nil
+		recorder = nil
 	}
 
 	var seasons []int
@@ -89,18 +288,77 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Println("Seasons: all available")
 	}
+	fmt.Printf("Season types: %v\n", seasonTypes)
+	if len(statuses) > 0 {
+		fmt.Printf("Statuses: %v\n", statuses)
+	} else {
+		fmt.Println("Statuses: provider default")
+	}
+	if len(gameIDs) > 0 {
+		fmt.Printf("Game ids: %d requested\n", len(gameIDs))
+	}
+	if !filter.From.IsZero() {
+		fmt.Printf("From: %s\n", filter.From.Format(time.DateOnly))
+	}
+	if !filter.To.IsZero() {
+		fmt.Printf("To: %s\n", filter.To.Format(time.DateOnly))
+	}
+	if len(filter.Teams) > 0 {
+		fmt.Printf("Teams: %v\n", filter.Teams)
+	}
 	fmt.Printf("Concurrency: %d\n", concurrency)
+	fmt.Printf("Max attempts per request: %d\n", retryPolicy.MaxAttempts)
+	if maxCalls > 0 {
+		fmt.Printf("Max calls: %d\n", maxCalls)
+	}
 	fmt.Printf("Output directory: %s\n", outputDir)
+	fmt.Printf("Compression: %s\n", storage.Compression)
+	if incremental {
+		fmt.Println("Incremental: skipping games already up to date")
+	}
+	if dryRun {
+		fmt.Println("Dry run: only fetching seasons and schedules")
+	}
 
 	config := download.Config{
 		Competition: competition,
 		Provider:    provider,
-		Seasons:     seasons,
-		Concurrency: concurrency,
-		OutputDir:   outputDir,
+		DownloadOptions: common.DownloadOptions{
+			Seasons:               seasons,
+			SeasonTypes:           seasonTypes,
+			Statuses:              statuses,
+			GameIDs:               gameIDs,
+			Filter:                filter,
+			Concurrency:           concurrency,
+			OutputDir:             outputDir,
+			Storage:               storage,
+			Incremental:           incremental,
+			DryRun:                dryRun,
+			PlanFile:              planFile,
+			Verbose:               verbose,
+			ReportFile:            reportFile,
+			Retry:                 retryPolicy,
+			RateLimits:            rateLimits,
+			SportRadarKeys:        sportRadarKeys(),
+			SportRadarAPIs:        sportRadarAPIs(competition, "download"),
+			KeyRotation:           keyRotation,
+			Usage:                 recorder,
+			HTTP:                  httpOpts,
+			SportRadarBaseURL:     viper.GetString("sportradar.base-url"),
+			BetGeniusURLs:         betGeniusURLs(),
+			BetGeniusCompetitions: betGeniusCompetitions(),
+		},
 	}
 
-	if err := download.Run(config); err != nil {
+	ctx, stop := interruptContext(cmd.Context())
+	defer stop()
+	defer saveUsageLedger(ledger)
+
+	err = download.Run(ctx, config)
+	if replayErr := checkReplay(replayer); replayErr != nil && err == nil {
+		err = replayErr
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Download failed: %v\n", err)
 		return err
 	}
@@ -108,6 +366,42 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// retryPolicyOf returns the retry policy set by the options of a command
+func retryPolicyOf(command string) retry.Policy {
+	return retry.Policy{
+		MaxAttempts:       viper.GetInt(command + ".max-attempts"),
+		RetryableStatuses: viper.GetIntSlice(command + ".retry-statuses"),
+		BaseDelay:         viper.GetDuration(command + ".retry-base-delay"),
+		MaxDelay:          viper.GetDuration(command + ".retry-max-delay"),
+	}
+}
+
+// rateLimitsOf returns the rate limits of each provider set by the options of a command
+func rateLimitsOf(command string) map[string]ratelimit.Rates {
+	return map[string]ratelimit.Rates{
+		"sportradar": {
+			ratelimit.Schedule: viper.GetFloat64(command + ".rate-limits.sportradar.schedule"),
+			ratelimit.Pbp:      viper.GetFloat64(command + ".rate-limits.sportradar.pbp"),
+		},
+		"betgenius": {
+			ratelimit.Schedule: viper.GetFloat64(command + ".rate-limits.betgenius.schedule"),
+			ratelimit.Pbp:      viper.GetFloat64(command + ".rate-limits.betgenius.pbp"),
+		},
+	}
+}
+
+// quoteValues lists the allowed values of a flag for its help text, e.g. "'a', 'b' or 'c'"
+func quoteValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + value + "'"
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -116,3 +410,171 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+// sportRadarKeys returns the SportRadar api keys of the configuration, keyed by competition
+func sportRadarKeys() map[string][]string {
+	keys := make(map[string][]string)
+	for _, competition := range sportsradar.Competitions {
+		for _, key := range viper.GetStringSlice("sportradar.keys." + competition) {
+			if key = strings.TrimSpace(key); key != "" {
+				keys[competition] = append(keys[competition], key)
+			}
+		}
+	}
+	return keys
+}
+
+// sportRadarAPIs returns the SportRadar APIs of the configuration, keyed by competition.
+// The sr-access-level, sr-api-version and sr-locale options of the command apply to the competition of the run.
+func sportRadarAPIs(competition, command string) map[string]common.SportRadarAPI {
+	apis := make(map[string]common.SportRadarAPI)
+	for _, c := range sportsradar.Competitions {
+		key := "sportradar.api." + c
+		api := common.SportRadarAPI{
+			AccessLevel: viper.GetString(key + ".access-level"),
+			Version:     viper.GetString(key + ".version"),
+			Locale:      viper.GetString(key + ".locale"),
+		}
+		if c == competition {
+			api.AccessLevel = firstNonEmpty(viper.GetString(command+".sr-access-level"), api.AccessLevel)
+			api.Version = firstNonEmpty(viper.GetString(command+".sr-api-version"), api.Version)
+			api.Locale = firstNonEmpty(viper.GetString(command+".sr-locale"), api.Locale)
+		}
+		apis[c] = api
+	}
+	return apis
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func accessLevelNames() []string {
+	names := make([]string, 0, len(sportsradar.AccessLevels))
+	for _, level := range sportsradar.AccessLevels {
+		names = append(names, string(level))
+	}
+	return names
+}
+
+func rotationNames() []string {
+	names := make([]string, 0, len(sportsradar.Rotations))
+	for _, rotation := range sportsradar.Rotations {
+		names = append(names, string(rotation))
+	}
+	return names
+}
+
+func parseKeyRotation(value string) (string, error) {
+	if !contains(rotationNames(), value) {
+		return "", fmt.Errorf("invalid key rotation %s, allowed values: %s", value, strings.Join(rotationNames(), ", "))
+	}
+	return value, nil
+}
+
+// collectGameIDs merges the game ids given as flag values with the ones listed in a file, dropping duplicates.
+// The file is either a list of ids or a plan written by a dry run.
+func collectGameIDs(ids []string, file string) ([]string, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading game ids file: %w", err)
+		}
+		if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+			planIDs, err := engine.ReadPlanGameIDs(data)
+			if err != nil {
+				return nil, fmt.Errorf("reading game ids file: %w", err)
+			}
+			data = []byte(strings.Join(planIDs, "\n"))
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			ids = append(ids, strings.Split(line, ",")...)
+		}
+	}
+
+	gameIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !contains(gameIDs, id) {
+			gameIDs = append(gameIDs, id)
+		}
+	}
+	return gameIDs, nil
+}
+
+// parseGameFilter builds the game filter from dates in YYYY-MM-DD format, interpreted in local time, and team identifiers
+func parseGameFilter(from, to string, teams []string) (common.GameFilter, error) {
+	filter := common.GameFilter{}
+
+	if from != "" {
+		date, err := time.ParseInLocation(time.DateOnly, from, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from date %s: %w", from, err)
+		}
+		filter.From = date
+	}
+
+	if to != "" {
+		date, err := time.ParseInLocation(time.DateOnly, to, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to date %s: %w", to, err)
+		}
+		filter.To = date
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, fmt.Errorf("to date %s is before from date %s", to, from)
+	}
+
+	for _, team := range teams {
+		if team = strings.TrimSpace(team); team != "" {
+			filter.Teams = append(filter.Teams, team)
+		}
+	}
+
+	return filter, nil
+}
+
+// validSeasonTypes are the season type codes used by the providers:
+// preseason, regular season, postseason, conference tournaments, NBA in-season tournament and play-in
+var validSeasonTypes = []string{"PRE", "REG", "PST", "CT", "IST", "PIT"}
+
+func parseSeasonTypes(values []string) ([]string, error) {
+	seasonTypes := make([]string, 0, len(values))
+	for _, v := range values {
+		seasonType := strings.ToUpper(strings.TrimSpace(v))
+		if !contains(validSeasonTypes, seasonType) {
+			return nil, fmt.Errorf("invalid season type %s. Valid options: %s", v, strings.Join(validSeasonTypes, ", "))
+		}
+		if !contains(seasonTypes, seasonType) {
+			seasonTypes = append(seasonTypes, seasonType)
+		}
+	}
+	if len(seasonTypes) == 0 {
+		return nil, fmt.Errorf("at least one season type is required")
+	}
+	return seasonTypes, nil
+}
+
+// validCompressions are the codecs game files can be compressed with
+var validCompressions = []string{common.NoCompression, common.Gzip}
+
+func parseStorage(compression string, compact bool) (common.Storage, error) {
+	compression = strings.ToLower(strings.TrimSpace(compression))
+	if compression == "" {
+		compression = common.NoCompression
+	}
+	if !contains(validCompressions, compression) {
+		return common.Storage{}, fmt.Errorf("invalid compression %s. Valid options: %s", compression, strings.Join(validCompressions, ", "))
+	}
+	return common.Storage{Compression: compression, Compact: compact}, nil
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gamedl/internal/common"
+	"gamedl/lib/web/clients/cassette"
+	"gamedl/lib/web/clients/httpclient"
+
+	"github.com/spf13/viper"
+)
+
+func init() {
+	rootCmd.PersistentFlags().DurationP("http-timeout", "", 0, "Maximum duration of each attempt of a request to the providers, reading the response included (default: no limit)")
+	rootCmd.PersistentFlags().StringP("proxy", "", "", "Proxy url the requests to the providers go through, e.g. 'http://proxy:3128' (default: the HTTPS_PROXY environment variable)")
+	rootCmd.PersistentFlags().StringP("user-agent", "", "", "User-Agent header of the requests to the providers (default: the Go http client one)")
+
+	viper.BindPFlag("http.timeout", rootCmd.PersistentFlags().Lookup("http-timeout"))
+	viper.BindPFlag("http.proxy", rootCmd.PersistentFlags().Lookup("proxy"))
+	viper.BindPFlag("http.user-agent", rootCmd.PersistentFlags().Lookup("user-agent"))
+
+	viper.BindEnv("http.timeout", "GAMEDL_HTTP_TIMEOUT")
+	viper.BindEnv("http.proxy", "GAMEDL_HTTP_PROXY")
+	viper.BindEnv("http.user-agent", "GAMEDL_HTTP_USER_AGENT")
+
+	viper.BindEnv("sportradar.base-url", "GAMEDL_SPORTRADAR_BASE_URL")
+	viper.BindEnv("betgenius.urls.base", "GAMEDL_BETGENIUS_BASE_URL")
+	viper.BindEnv("betgenius.urls.auth-v1", "GAMEDL_BETGENIUS_AUTH_V1_URL")
+	viper.BindEnv("betgenius.urls.oauth", "GAMEDL_BETGENIUS_OAUTH_URL")
+	viper.BindEnv("betgenius.urls.fixtures-v1", "GAMEDL_BETGENIUS_FIXTURES_V1_URL")
+	viper.BindEnv("betgenius.urls.fixtures-v2", "GAMEDL_BETGENIUS_FIXTURES_V2_URL")
+	for _, competition := range betGeniusNamedCompetitions {
+		viper.BindEnv("betgenius.competitions."+competition, "GAMEDL_BETGENIUS_"+strings.ToUpper(competition)+"_COMPETITION")
+	}
+}
+
+// betGeniusNamedCompetitions are the competitions downloaded from a BetGenius competition looked up by name
+var betGeniusNamedCompetitions = []string{"ncaab", "ncaaf"}
+
+// httpOptions returns the transport settings of the provider clients from the configuration
+func httpOptions() (common.HTTPOptions, error) {
+	opts := common.HTTPOptions{
+		Timeout:   viper.GetDuration("http.timeout"),
+		Proxy:     viper.GetString("http.proxy"),
+		UserAgent: viper.GetString("http.user-agent"),
+	}
+	if opts.Timeout < 0 {
+		return opts, fmt.Errorf("http timeout must not be negative")
+	}
+	if _, err := opts.ProxyURL(); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// betGeniusURLs returns the BetGenius urls overridden in the configuration
+func betGeniusURLs() common.BetGeniusURLs {
+	return common.BetGeniusURLs{
+		Base:       viper.GetString("betgenius.urls.base"),
+		AuthV1:     viper.GetString("betgenius.urls.auth-v1"),
+		OAuth:      viper.GetString("betgenius.urls.oauth"),
+		FixturesV1: viper.GetString("betgenius.urls.fixtures-v1"),
+		FixturesV2: viper.GetString("betgenius.urls.fixtures-v2"),
+	}
+}
+
+// betGeniusCompetitions returns the ids or names of the BetGenius competitions of the configuration, keyed by competition
+func betGeniusCompetitions() map[string]string {
+	competitions := make(map[string]string)
+	for _, competition := range betGeniusNamedCompetitions {
+		if value := strings.TrimSpace(viper.GetString("betgenius.competitions." + competition)); value != "" {
+			competitions[competition] = value
+		}
+	}
+	return competitions
+}
+
+// useCassette sets the transport of opts to record the requests of the run to the record directory,
+// or to replay the ones recorded in the replay directory, which it returns the replayer of
+func useCassette(opts *common.HTTPOptions, record, replay string) (*cassette.Replayer, error) {
+	if record != "" && replay != "" {
+		return nil, fmt.Errorf("record and replay can't be used together")
+	}
+
+	if record != "" {
+		proxy, err := opts.ProxyURL()
+		if err != nil {
+			return nil, err
+		}
+		recorder, err := cassette.NewRecorder(record, httpclient.DefaultTransport(proxy))
+		if err != nil {
+			return nil, err
+		}
+		opts.Transport = recorder
+		fmt.Printf("Recording requests to %s\n", record)
+		return nil, nil
+	}
+
+	if replay != "" {
+		replayer, err := cassette.LoadReplayer(replay)
+		if err != nil {
+			return nil, err
+		}
+		opts.Transport = replayer
+		opts.Replay = true
+		fmt.Printf("Replaying requests from %s\n", replay)
+		return replayer, nil
+	}
+
+	return nil, nil
+}
+
+// checkReplay prints the requests of a replay that had no recorded response, and fails when there are any
+func checkReplay(replayer *cassette.Replayer) error {
+	if replayer == nil {
+		return nil
+	}
+	unmatched := replayer.Unmatched()
+	if len(unmatched) == 0 {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Requests without a recorded response:\n")
+	for _, request := range unmatched {
+		fmt.Fprintf(os.Stderr, "  %s\n", request)
+	}
+	return fmt.Errorf("replay: %d requests had no recorded response", len(unmatched))
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"gamedl/internal/registry"
+
+	"github.com/spf13/cobra"
+)
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "List the supported providers and their competitions",
+	Long: `List the data providers, the aliases they can be given by, and the competitions
+each of them can download and watch, with the directory their games are saved into.`,
+	Run: runProviders,
+}
+
+func init() {
+	rootCmd.AddCommand(providersCmd)
+}
+
+func runProviders(cmd *cobra.Command, args []string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tALIASES\tCOMPETITION\tDIRECTORY\tWATCH")
+	for _, provider := range registry.Providers() {
+		for _, competition := range provider.Competitions() {
+			watch := "no"
+			if competition.Watch != nil {
+				watch = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", provider.Name, strings.Join(provider.Aliases, ", "), competition.Name, competition.Directory, watch)
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gamedl/lib/web/clients/usage"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Report the API calls made with each credential",
+	Long: `Report the calls recorded in the usage ledger for every key of each credential,
+by endpoint, with what is left of the monthly budgets set in the configuration.
+Keys are shown by a short fingerprint, never in clear.`,
+	RunE: runUsage,
+}
+
+// usageCredentials are the credentials calls are counted against, as named in the ledger and the budgets
+var usageCredentials = []string{
+	"sportradar/nba",
+	"sportradar/ncaab",
+	"sportradar/ncaaf",
+	"sportradar/nfl",
+	"betgenius/fixtures",
+	"betgenius/stats",
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+
+	rootCmd.PersistentFlags().StringP("usage-file", "", "", "Usage ledger the API calls are counted in (default: <user config dir>/gamedl/usage.json)")
+	viper.BindPFlag("usage.file", rootCmd.PersistentFlags().Lookup("usage-file"))
+	viper.BindEnv("usage.file", "GAMEDL_USAGE_FILE")
+	for _, credential := range usageCredentials {
+		env := "GAMEDL_USAGE_BUDGET_" + strings.ToUpper(strings.ReplaceAll(credential, "/", "_"))
+		viper.BindEnv(budgetKey(credential), env)
+	}
+
+	usageCmd.Flags().StringP("month", "m", "", "Month to report, in YYYY-MM format (default: the current month)")
+	usageCmd.Flags().IntP("days", "d", 0, "Also report the calls of each of the last days")
+
+	viper.BindPFlag("usage.month", usageCmd.Flags().Lookup("month"))
+	viper.BindPFlag("usage.days", usageCmd.Flags().Lookup("days"))
+
+	viper.BindEnv("usage.month", "GAMEDL_USAGE_MONTH")
+	viper.BindEnv("usage.days", "GAMEDL_USAGE_DAYS")
+}
+
+// budgetKey returns the configuration key of the monthly budget of a credential, e.g. usage.budgets.sportradar.nba
+func budgetKey(credential string) string {
+	return "usage.budgets." + strings.ReplaceAll(credential, "/", ".")
+}
+
+// usageBudgets returns the monthly budgets of the configuration, with the calls allowed to the run
+func usageBudgets(maxCalls int) usage.Budgets {
+	budgets := usage.Budgets{MaxCalls: maxCalls, Monthly: make(map[string]int)}
+	for _, credential := range usageCredentials {
+		if budget := viper.GetInt(budgetKey(credential)); budget > 0 {
+			budgets.Monthly[credential] = budget
+		}
+	}
+	return budgets
+}
+
+// usageFile returns the path of the usage ledger
+func usageFile() (string, error) {
+	if path := viper.GetString("usage.file"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding usage ledger directory: %w", err)
+	}
+	return filepath.Join(dir, "gamedl", "usage.json"), nil
+}
+
+// loadUsageLedger loads the usage ledger with the budgets of the configuration
+func loadUsageLedger(maxCalls int) (*usage.Ledger, error) {
+	if maxCalls < 0 {
+		return nil, fmt.Errorf("max calls must not be negative")
+	}
+	path, err := usageFile()
+	if err != nil {
+		return nil, err
+	}
+	return usage.LoadLedger(path, usageBudgets(maxCalls))
+}
+
+// saveUsageLedger adds the calls of the run to the usage ledger and prints how many were made
+func saveUsageLedger(ledger *usage.Ledger) {
+	fmt.Printf("API calls: %d\n", ledger.RunCalls())
+	if err := ledger.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving usage ledger: %v\n", err)
+	}
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	month := viper.GetString("usage.month")
+	if month == "" {
+		month = now.Format(usage.MonthLayout)
+	} else if _, err := time.Parse(usage.MonthLayout, month); err != nil {
+		return fmt.Errorf("invalid month %s: %w", month, err)
+	}
+	days := viper.GetInt("usage.days")
+	if days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+
+	ledger, err := loadUsageLedger(0)
+	if err != nil {
+		return err
+	}
+	path, err := usageFile()
+	if err != nil {
+		return err
+	}
+	budgets := usageBudgets(0)
+	today := now.Format(usage.DayLayout)
+
+	fmt.Printf("Usage ledger: %s\n", path)
+	fmt.Printf("Month: %s\n\n", month)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CREDENTIAL\tKEY\tENDPOINT\tMONTH\tTODAY\tBUDGET\tREMAINING")
+	for _, key := range ledger.Keys() {
+		calls := key.Month(month)
+		if len(calls) == 0 {
+			continue
+		}
+		endpoints := make([]string, 0, len(calls))
+		for endpoint := range calls {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+		for _, endpoint := range endpoints {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t\t\n", key.Credential, key.KeyID, endpoint, calls[endpoint], key.Days[today][endpoint])
+		}
+
+		total := usage.Total(calls)
+		budget, remaining := "-", "-"
+		if b := budgets.Monthly[key.Credential]; b > 0 {
+			budget, remaining = strconv.Itoa(b), strconv.Itoa(max(b-total, 0))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n", key.Credential, key.KeyID, "total", total, usage.Total(key.Days[today]), budget, remaining)
+	}
+	w.Flush()
+
+	if days > 0 {
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DAY\tCREDENTIAL\tKEY\tCALLS")
+		for i := days - 1; i >= 0; i-- {
+			day := now.AddDate(0, 0, -i).Format(usage.DayLayout)
+			for _, key := range ledger.Keys() {
+				if calls := usage.Total(key.Days[day]); calls > 0 {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", day, key.Credential, key.KeyID, calls)
+				}
+			}
+		}
+		w.Flush()
+	}
+
+	return nil
+}
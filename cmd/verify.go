@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gamedl/internal/registry"
+	"gamedl/internal/verify"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check downloaded game files against their manifests",
+	Long: `Check every game file recorded in the season manifests of the dataset against the
+SHA-256 checksum and size recorded when it was downloaded, and list the files that are
+missing, truncated or modified. Games downloaded before checksums were recorded are only
+checked for holding valid JSON.
+`,
+	RunE: runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringP("input-dir", "i", "downloaded_games", "Directory containing downloaded game files")
+	verifyCmd.Flags().StringP("competition", "c", "", "Only check the games of this competition (values allowed: "+quoteValues(registry.CompetitionNames())+") (default: all competitions)")
+	verifyCmd.Flags().StringP("provider", "p", "", "Only check the games of this provider (values allowed: "+quoteValues(registry.ProviderNames())+") (default: all providers)")
+
+	viper.BindPFlag("verify.input-dir", verifyCmd.Flags().Lookup("input-dir"))
+	viper.BindPFlag("verify.competition", verifyCmd.Flags().Lookup("competition"))
+	viper.BindPFlag("verify.provider", verifyCmd.Flags().Lookup("provider"))
+
+	viper.BindEnv("verify.input-dir", "GAMEDL_VERIFY_INPUT_DIR")
+	viper.BindEnv("verify.competition", "GAMEDL_VERIFY_COMPETITION")
+	viper.BindEnv("verify.provider", "GAMEDL_VERIFY_PROVIDER")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	directories, err := verifyDirectories(viper.GetString("verify.provider"), viper.GetString("verify.competition"))
+	if err != nil {
+		return err
+	}
+	config := verify.Config{
+		InputDir:    viper.GetString("verify.input-dir"),
+		Directories: directories,
+	}
+
+	fmt.Printf("Verifying %s\n", config.InputDir)
+
+	if err := verify.Run(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Verify failed: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// verifyDirectories returns the directories the games of a competition and provider are saved into,
+// every provider's when no provider is given, or nil to check every directory when neither is
+func verifyDirectories(provider, competition string) ([]string, error) {
+	if provider == "" && competition == "" {
+		return nil, nil
+	}
+	if provider != "" && competition != "" {
+		_, c, err := registry.LookupCompetition(provider, competition)
+		if err != nil {
+			return nil, err
+		}
+		return []string{c.Directory}, nil
+	}
+
+	providers := registry.Providers()
+	if provider != "" {
+		p, err := registry.LookupProvider(provider)
+		if err != nil {
+			return nil, err
+		}
+		providers = []*registry.Provider{p}
+	}
+	var directories []string
+	for _, p := range providers {
+		for _, c := range p.Competitions() {
+			if competition == "" || c.Name == competition {
+				directories = append(directories, c.Directory)
+			}
+		}
+	}
+	if len(directories) == 0 {
+		return nil, fmt.Errorf("invalid competition %s. Valid options: %s", competition, strings.Join(registry.CompetitionNames(), ", "))
+	}
+	return directories, nil
+}
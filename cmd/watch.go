@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gamedl/internal/common"
+	"gamedl/internal/download"
+	"gamedl/internal/registry"
+	"gamedl/lib/web/clients/retry"
+	"gamedl/lib/web/clients/sportsradar"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Follow games in progress and keep snapshots of their play by play",
+	Long: `Poll the schedule for games in progress and refetch their play by play at a fixed interval.
+Every payload that changed is saved as a timestamped snapshot next to the game file,
+and a game stops being polled once it is closed. The command ends once the last game
+scheduled up to the --to date is over, waiting for the games that haven't started yet.
+Supports SportRadar NBA and NFL, and BetGenius NFL.
+`,
+	RunE: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	defaultRetry := retry.DefaultPolicy()
+
+	watchCmd.Flags().StringP("competition", "c", "", "Competition to watch (values allowed: "+quoteValues(registry.WatchableCompetitionNames())+", see 'gamedl providers' for the ones of each provider) (required)")
+	watchCmd.Flags().StringP("provider", "p", "", "Data provider (values allowed: "+quoteValues(registry.ProviderNames())+") (required)")
+	watchCmd.Flags().StringSliceP("seasons", "s", nil, "Seasons to watch, comma-separated (default: the two latest seasons)")
+	watchCmd.Flags().StringSliceP("season-types", "", []string{common.RegularSeason}, "Season types to watch, comma-separated. e.g 'REG,PST' (values allowed: "+strings.Join(validSeasonTypes, ", ")+")")
+	watchCmd.Flags().StringSliceP("team", "", nil, "Only watch games of these teams, comma-separated")
+	watchCmd.Flags().StringP("to", "", "", "Keep watching until the games scheduled on or before this date are over, in YYYY-MM-DD format (default: today)")
+	watchCmd.Flags().DurationP("interval", "i", defaultWatchInterval, "Time between two polls")
+	watchCmd.Flags().StringP("output-dir", "o", "downloaded_games", "Directory to store game files and snapshots")
+	watchCmd.Flags().StringP("compression", "", common.NoCompression, "Compression of saved game files and snapshots (values allowed: 'none' or 'gzip')")
+	watchCmd.Flags().BoolP("compact", "", false, "Save game files and snapshots as received from the provider instead of indenting them")
+	watchCmd.Flags().StringP("sr-key-rotation", "", string(sportsradar.RoundRobin), "How SportRadar requests pick among the api keys of a competition (values allowed: "+quoteValues(rotationNames())+")")
+	watchCmd.Flags().StringP("sr-access-level", "", "", "Access level of the SportRadar api keys (values allowed: "+quoteValues(accessLevelNames())+") (default: sportradar.api.<competition>.access-level, or 'trial')")
+	watchCmd.Flags().StringP("sr-api-version", "", "", "Version of the SportRadar API, e.g. 'v8' (default: sportradar.api.<competition>.version, or the latest version supported)")
+	watchCmd.Flags().StringP("sr-locale", "", "", "Language of the SportRadar payload descriptions, e.g. 'es' (default: sportradar.api.<competition>.locale, or 'en')")
+	watchCmd.Flags().IntP("max-calls", "", 0, "Maximum number of API calls of the run, retries included. The watch ends when they are made (default: unlimited)")
+	watchCmd.Flags().IntP("max-attempts", "", defaultRetry.MaxAttempts, "Maximum number of attempts per request, including the first one")
+	watchCmd.Flags().IntSliceP("retry-statuses", "", defaultRetry.RetryableStatuses, "HTTP status codes that are retried, comma-separated")
+	watchCmd.Flags().DurationP("retry-base-delay", "", defaultRetry.BaseDelay, "Delay before the first retry, doubled on every following retry (a Retry-After header takes precedence)")
+	watchCmd.Flags().DurationP("retry-max-delay", "", defaultRetry.MaxDelay, "Maximum delay between retries")
+	watchCmd.Flags().Float64P("sr-schedule-rps", "", 0, "Maximum SportRadar season and schedule requests per second (default: unlimited)")
+	watchCmd.Flags().Float64P("sr-pbp-rps", "", 0, "Maximum SportRadar play by play requests per second (default: unlimited)")
+	watchCmd.Flags().Float64P("bg-schedule-rps", "", 0, "Maximum BetGenius season and fixture requests per second (default: unlimited)")
+	watchCmd.Flags().Float64P("bg-pbp-rps", "", 0, "Maximum BetGenius play by play requests per second (default: unlimited)")
+
+	viper.BindPFlag("watch.competition", watchCmd.Flags().Lookup("competition"))
+	viper.BindPFlag("watch.provider", watchCmd.Flags().Lookup("provider"))
+	viper.BindPFlag("watch.seasons", watchCmd.Flags().Lookup("seasons"))
+	viper.BindPFlag("watch.season-types", watchCmd.Flags().Lookup("season-types"))
+	viper.BindPFlag("watch.team", watchCmd.Flags().Lookup("team"))
+	viper.BindPFlag("watch.to", watchCmd.Flags().Lookup("to"))
+	viper.BindPFlag("watch.interval", watchCmd.Flags().Lookup("interval"))
+	viper.BindPFlag("watch.output-dir", watchCmd.Flags().Lookup("output-dir"))
+	viper.BindPFlag("watch.compression", watchCmd.Flags().Lookup("compression"))
+	viper.BindPFlag("watch.compact", watchCmd.Flags().Lookup("compact"))
+	viper.BindPFlag("watch.sr-key-rotation", watchCmd.Flags().Lookup("sr-key-rotation"))
+	viper.BindPFlag("watch.sr-access-level", watchCmd.Flags().Lookup("sr-access-level"))
+	viper.BindPFlag("watch.sr-api-version", watchCmd.Flags().Lookup("sr-api-version"))
+	viper.BindPFlag("watch.sr-locale", watchCmd.Flags().Lookup("sr-locale"))
+	viper.BindPFlag("watch.max-calls", watchCmd.Flags().Lookup("max-calls"))
+	viper.BindPFlag("watch.max-attempts", watchCmd.Flags().Lookup("max-attempts"))
+	viper.BindPFlag("watch.retry-statuses", watchCmd.Flags().Lookup("retry-statuses"))
+	viper.BindPFlag("watch.retry-base-delay", watchCmd.Flags().Lookup("retry-base-delay"))
+	viper.BindPFlag("watch.retry-max-delay", watchCmd.Flags().Lookup("retry-max-delay"))
+	viper.BindPFlag("watch.rate-limits.sportradar.schedule", watchCmd.Flags().Lookup("sr-schedule-rps"))
+	viper.BindPFlag("watch.rate-limits.sportradar.pbp", watchCmd.Flags().Lookup("sr-pbp-rps"))
+	viper.BindPFlag("watch.rate-limits.betgenius.schedule", watchCmd.Flags().Lookup("bg-schedule-rps"))
+	viper.BindPFlag("watch.rate-limits.betgenius.pbp", watchCmd.Flags().Lookup("bg-pbp-rps"))
+
+	viper.BindEnv("watch.competition", "GAMEDL_WATCH_COMPETITION")
+	viper.BindEnv("watch.provider", "GAMEDL_WATCH_PROVIDER")
+	viper.BindEnv("watch.seasons", "GAMEDL_WATCH_SEASONS")
+	viper.BindEnv("watch.season-types", "GAMEDL_WATCH_SEASON_TYPES")
+	viper.BindEnv("watch.team", "GAMEDL_WATCH_TEAM")
+	viper.BindEnv("watch.to", "GAMEDL_WATCH_TO")
+	viper.BindEnv("watch.interval", "GAMEDL_WATCH_INTERVAL")
+	viper.BindEnv("watch.output-dir", "GAMEDL_WATCH_OUTPUT_DIR")
+	viper.BindEnv("watch.compression", "GAMEDL_WATCH_COMPRESSION")
+	viper.BindEnv("watch.compact", "GAMEDL_WATCH_COMPACT")
+	viper.BindEnv("watch.sr-key-rotation", "GAMEDL_WATCH_SR_KEY_ROTATION")
+	viper.BindEnv("watch.sr-access-level", "GAMEDL_WATCH_SR_ACCESS_LEVEL")
+	viper.BindEnv("watch.sr-api-version", "GAMEDL_WATCH_SR_API_VERSION")
+	viper.BindEnv("watch.sr-locale", "GAMEDL_WATCH_SR_LOCALE")
+	viper.BindEnv("watch.max-calls", "GAMEDL_WATCH_MAX_CALLS")
+	viper.BindEnv("watch.max-attempts", "GAMEDL_WATCH_MAX_ATTEMPTS")
+	viper.BindEnv("watch.retry-statuses", "GAMEDL_WATCH_RETRY_STATUSES")
+	viper.BindEnv("watch.retry-base-delay", "GAMEDL_WATCH_RETRY_BASE_DELAY")
+	viper.BindEnv("watch.retry-max-delay", "GAMEDL_WATCH_RETRY_MAX_DELAY")
+	viper.BindEnv("watch.rate-limits.sportradar.schedule", "GAMEDL_WATCH_SR_SCHEDULE_RPS")
+	viper.BindEnv("watch.rate-limits.sportradar.pbp", "GAMEDL_WATCH_SR_PBP_RPS")
+	viper.BindEnv("watch.rate-limits.betgenius.schedule", "GAMEDL_WATCH_BG_SCHEDULE_RPS")
+	viper.BindEnv("watch.rate-limits.betgenius.pbp", "GAMEDL_WATCH_BG_PBP_RPS")
+}
+
+const defaultWatchInterval = 30 * time.Second
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	competition := viper.GetString("watch.competition")
+	provider := viper.GetString("watch.provider")
+	interval := viper.GetDuration("watch.interval")
+	outputDir := viper.GetString("watch.output-dir")
+	to := viper.GetString("watch.to")
+	if to == "" {
+		to = time.Now().Format(time.DateOnly)
+	}
+	filter, err := parseGameFilter("", to, viper.GetStringSlice("watch.team"))
+	if err != nil {
+		return err
+	}
+	seasonTypes, err := parseSeasonTypes(viper.GetStringSlice("watch.season-types"))
+	if err != nil {
+		return err
+	}
+	storage, err := parseStorage(viper.GetString("watch.compression"), viper.GetBool("watch.compact"))
+	if err != nil {
+		return err
+	}
+
+	if competition == "" {
+		return fmt.Errorf("competition is required")
+	}
+
+	if provider == "" {
+		return fmt.Errorf("provider is required")
+	}
+
+	if _, _, err := registry.LookupCompetition(provider, competition); err != nil {
+		return err
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	retryPolicy := retryPolicyOf("watch")
+	if retryPolicy.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1")
+	}
+
+	var seasons []int
+	for _, s := range viper.GetStringSlice("watch.seasons") {
+		season, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("invalid season %s: %w", s, err)
+		}
+		seasons = append(seasons, season)
+	}
+
+	keyRotation, err := parseKeyRotation(viper.GetString("watch.sr-key-rotation"))
+	if err != nil {
+		return err
+	}
+
+	httpOpts, err := httpOptions()
+	if err != nil {
+		return err
+	}
+
+	maxCalls := viper.GetInt("watch.max-calls")
+	ledger, err := loadUsageLedger(maxCalls)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Watching %s games from %s\n", competition, provider)
+	fmt.Printf("Interval: %v\n", interval)
+	fmt.Printf("Output directory: %s\n", outputDir)
+
+	config := download.WatchConfig{
+		Config: download.Config{
+			Competition: competition,
+			Provider:    provider,
+			DownloadOptions: common.DownloadOptions{
+				Seasons:               seasons,
+				SeasonTypes:           seasonTypes,
+				Filter:                filter,
+				OutputDir:             outputDir,
+				Storage:               storage,
+				Retry:                 retryPolicy,
+				RateLimits:            rateLimitsOf("watch"),
+				SportRadarKeys:        sportRadarKeys(),
+				SportRadarAPIs:        sportRadarAPIs(competition, "watch"),
+				KeyRotation:           keyRotation,
+				Usage:                 ledger,
+				HTTP:                  httpOpts,
+				SportRadarBaseURL:     viper.GetString("sportradar.base-url"),
+				BetGeniusURLs:         betGeniusURLs(),
+				BetGeniusCompetitions: betGeniusCompetitions(),
+			},
+		},
+		Interval: interval,
+	}
+
+	ctx, stop := interruptContext(cmd.Context())
+	defer stop()
+	defer saveUsageLedger(ledger)
+
+	if err := download.Watch(ctx, config); err != nil {
+		fmt.Fprintf(os.Stderr, "Watch failed: %v\n", err)
+		return err
+	}
+
+	return nil
+}
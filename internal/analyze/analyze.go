@@ -2,12 +2,14 @@ package analyze
 
 import (
 	"fmt"
+	"path/filepath"
 
-	"gamedl/internal/analyze/nba"
-	"gamedl/internal/analyze/ncaab"
-	"gamedl/internal/analyze/ncaaf"
-	"gamedl/internal/analyze/nfl"
+	_ "gamedl/internal/analyze/nba"
+	_ "gamedl/internal/analyze/ncaab"
+	_ "gamedl/internal/analyze/ncaaf"
+	_ "gamedl/internal/analyze/nfl"
 	"gamedl/internal/common"
+	"gamedl/internal/registry"
 )
 
 type Config struct {
@@ -16,31 +18,39 @@ type Config struct {
 	InputDir     string
 	OutputDir    string
 	Seasons      []int
+	SeasonTypes  []string
 }
 
-// analysisRequiresYears returns true if the given analysis type requires year-based directory structure
-func analysisRequiresYears(competition, analysisType string) bool {
-	// player-stats analysis for NBA doesn't require years - it searches recursively
-	if competition == "nba" && analysisType == "player-stats" {
-		return false
+// seasonsToAnalyze returns every combination of the configured years and season types
+func (c Config) seasonsToAnalyze() []common.Season {
+	seasons := make([]common.Season, 0, len(c.Seasons)*len(c.SeasonTypes))
+	for _, year := range c.Seasons {
+		for _, seasonType := range c.SeasonTypes {
+			seasons = append(seasons, common.Season{Year: year, Type: seasonType})
+		}
 	}
-	return true
+	return seasons
 }
 
-func hydrateConfig(config *Config) error {
+func hydrateConfig(config *Config, analysis registry.Analysis) error {
 	// Skip year discovery for analyses that don't require it
-	if !analysisRequiresYears(config.Competition, config.AnalysisType) {
+	if !analysis.RequiresYears {
 		return nil
 	}
 
+	if len(config.SeasonTypes) == 0 {
+		config.SeasonTypes = []string{common.RegularSeason}
+	}
+
 	// If no years are specified, discover available years from directory structure
-	if len(config.Seasons) == 0 {
-		availableYears, err := common.GetAvailableYears(config.InputDir, config.Competition)
+	if len(config.seasonsToAnalyze()) == 0 {
+		directory := analysis.GamesDirectory()
+		availableYears, err := common.GetAvailableYears(config.InputDir, directory)
 		if err != nil {
 			return fmt.Errorf("failed to discover available years: %w", err)
 		}
 		if len(availableYears) == 0 {
-			return fmt.Errorf("no years found for competition %s in directory %s", config.Competition, config.InputDir)
+			return fmt.Errorf("no years found for competition %s in directory %s", config.Competition, filepath.Join(config.InputDir, directory))
 		}
 		config.Seasons = availableYears
 		fmt.Printf("Using available years: %v\n", config.Seasons)
@@ -50,68 +60,19 @@ func hydrateConfig(config *Config) error {
 }
 
 func Run(config Config) error {
-	if err := hydrateConfig(&config); err != nil {
-		return fmt.Errorf("hydrating config: %w", err)
-	}
-
-	switch config.Competition {
-	case "nfl":
-		return runNFLAnalysis(config)
-	case "ncaab":
-		return runNCAABAnalysis(config)
-	case "ncaaf":
-		return runNCAAFAnalysis(config)
-	case "nba":
-		return runNBAAnalysis(config)
-	default:
-		return fmt.Errorf("unsupported competition: %s", config.Competition)
-	}
-}
-
-func runNFLAnalysis(config Config) error {
-	analyzer := nfl.NewAnalyzer(config.InputDir, config.OutputDir)
-
-	switch config.AnalysisType {
-	case "action-types":
-		return analyzer.AnalyzeActionTypes(config.Seasons)
-	case "recoveries-in-conversions":
-		return analyzer.AnalyzeRecoveriesInConversions(config.Seasons)
-	default:
-		return fmt.Errorf("unsupported analysis type for NFL: %s", config.AnalysisType)
+	analysis, err := registry.LookupAnalysis(config.Competition, config.AnalysisType)
+	if err != nil {
+		return err
 	}
-}
-
-func runNCAABAnalysis(config Config) error {
-	analyzer := ncaab.NewAnalyzer(config.InputDir, config.OutputDir)
-
-	switch config.AnalysisType {
-	case "review-types":
-		return analyzer.AnalyzeReviewTypes(config.Seasons)
-	default:
-		return fmt.Errorf("unsupported analysis type for NCAAB: %s", config.AnalysisType)
-	}
-}
 
-func runNCAAFAnalysis(config Config) error {
-	analyzer := ncaaf.NewAnalyzer(config.InputDir, config.OutputDir)
-
-	switch config.AnalysisType {
-	case "review-types":
-		return analyzer.AnalyzeReviewTypes(config.Seasons)
-	default:
-		return fmt.Errorf("unsupported analysis type for NCAAF: %s", config.AnalysisType)
+	if err := hydrateConfig(&config, analysis); err != nil {
+		return fmt.Errorf("hydrating config: %w", err)
 	}
-}
 
-func runNBAAnalysis(config Config) error {
-	analyzer := nba.NewAnalyzer(config.InputDir, config.OutputDir)
-
-	switch config.AnalysisType {
-	case "lane-violations":
-		return analyzer.AnalyzeLaneViolations(config.Seasons)
-	case "player-stats":
-		return analyzer.AnalyzePlayerStats()
-	default:
-		return fmt.Errorf("unsupported analysis type for NBA: %s", config.AnalysisType)
-	}
+	return analysis.Run(registry.AnalysisOptions{
+		InputDir:  config.InputDir,
+		OutputDir: config.OutputDir,
+		Directory: analysis.GamesDirectory(),
+		Seasons:   config.seasonsToAnalyze(),
+	})
 }
@@ -3,9 +3,9 @@ package nba
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gamedl/internal/common"
 	"gamedl/lib/web/clients/sportsradar"
@@ -14,6 +14,8 @@ import (
 type Analyzer struct {
 	inputDir  string
 	outputDir string
+	// directory is the competition directory of the games, the one SportRadar NBA downloads are saved into
+	directory string
 }
 
 type ProcessResultNba struct {
@@ -32,6 +34,7 @@ type LaneViolationContext struct {
 
 type GameLaneViolations struct {
 	Year       int                    `json:"year"`
+	SeasonType string                 `json:"season_type"`
 	GameID     string                 `json:"game_id"`
 	Violations []LaneViolationContext `json:"violations"`
 }
@@ -49,10 +52,11 @@ type GameMissingPlayerStats struct {
 	EventTypes []string `json:"event_types"`
 }
 
-func NewAnalyzer(inputDir, outputDir string) *Analyzer {
+func NewAnalyzer(inputDir, outputDir, directory string) *Analyzer {
 	return &Analyzer{
 		inputDir:  inputDir,
 		outputDir: outputDir,
+		directory: directory,
 	}
 }
 
@@ -68,13 +72,7 @@ func NewProcessResultNba() ProcessResultNba {
 
 func (a *Analyzer) processFileNba(path string) (ProcessResultNba, error) {
 	result := NewProcessResultNba()
-	f, err := os.OpenFile(path, os.O_RDONLY, 0)
-	if err != nil {
-		return result, fmt.Errorf("could not open file %s: %w", path, err)
-	}
-	defer f.Close()
-
-	data, err := io.ReadAll(f)
+	data, err := common.ReadGameFile(path)
 	if err != nil {
 		return result, fmt.Errorf("could not read file %s: %w", path, err)
 	}
@@ -131,23 +129,22 @@ func (a *Analyzer) processFileNba(path string) (ProcessResultNba, error) {
 	return result, nil
 }
 
-func (a *Analyzer) AnalyzeLaneViolations(years []int) error {
+func (a *Analyzer) AnalyzeLaneViolations(seasons []common.Season) error {
 	var errs []error
 	eventTypeCount := make(map[string]int)
 	turnoverTypeCount := make(map[string]int)
-	gamesWithLaneViolations := make(map[string]int)         // gameID -> year
-	gamesWithLaneViolationTurnovers := make(map[string]int) // gameID -> year
+	gamesWithLaneViolations := make(map[string]common.Season)         // gameID -> season
+	gamesWithLaneViolationTurnovers := make(map[string]common.Season) // gameID -> season
 	gamesLaneViolationsContext := make([]GameLaneViolations, 0)
 
-	for _, year := range years {
-		path := common.GetYearGlobPattern(a.inputDir, "nba", year)
-		matches, err := filepath.Glob(path)
+	for _, season := range seasons {
+		matches, err := common.GetYearGameFiles(a.inputDir, a.directory, season.Year, season.Type)
 		if err != nil {
-			fmt.Printf("Error globbing files for year %d: %v\n", year, err)
+			fmt.Printf("Error globbing files for season %v: %v\n", season, err)
 			continue
 		}
 
-		fmt.Printf("year: %d, matches: %v\n", year, len(matches))
+		fmt.Printf("season: %v, matches: %v\n", season, len(matches))
 		for _, match := range matches {
 			result, err := a.processFileNba(match)
 			if err != nil {
@@ -167,11 +164,12 @@ func (a *Analyzer) AnalyzeLaneViolations(years []int) error {
 
 			// Track games with lane violations (event_type)
 			if result.HasLane {
-				gamesWithLaneViolations[result.ID] = year
+				gamesWithLaneViolations[result.ID] = season
 				// Store lane violations context for this game
 				if len(result.LaneViolations) > 0 {
 					gamesLaneViolationsContext = append(gamesLaneViolationsContext, GameLaneViolations{
-						Year:       year,
+						Year:       season.Year,
+						SeasonType: season.Type,
 						GameID:     result.ID,
 						Violations: result.LaneViolations,
 					})
@@ -180,7 +178,7 @@ func (a *Analyzer) AnalyzeLaneViolations(years []int) error {
 
 			// Track games with "Lane Violation" turnover_type
 			if result.HasLaneViolationTurnover {
-				gamesWithLaneViolationTurnovers[result.ID] = year
+				gamesWithLaneViolationTurnovers[result.ID] = season
 			}
 		}
 	}
@@ -205,9 +203,9 @@ func (a *Analyzer) AnalyzeLaneViolations(years []int) error {
 	if err := os.MkdirAll(laneViolationsGamesDir, 0o755); err != nil {
 		fmt.Printf("could not create lane_violations_games directory: %v\n", err)
 	} else {
-		for gameID, year := range gamesWithLaneViolations {
-			gameFile := common.GetGameFilePath(a.inputDir, "nba", year, gameID)
-			gameData, err := os.ReadFile(gameFile)
+		for gameID, season := range gamesWithLaneViolations {
+			gameFile := common.GetGameFilePath(a.inputDir, a.directory, season.Year, season.Type, gameID)
+			gameData, err := common.ReadGameFile(gameFile)
 			if err != nil {
 				fmt.Printf("could not read game file %s: %v\n", gameFile, err)
 				continue
@@ -229,9 +227,9 @@ func (a *Analyzer) AnalyzeLaneViolations(years []int) error {
 	if err := os.MkdirAll(laneViolationTurnoverGamesDir, 0o755); err != nil {
 		fmt.Printf("could not create lane_violation_turnover_games directory: %v\n", err)
 	} else {
-		for gameID, year := range gamesWithLaneViolationTurnovers {
-			gameFile := common.GetGameFilePath(a.inputDir, "nba", year, gameID)
-			gameData, err := os.ReadFile(gameFile)
+		for gameID, season := range gamesWithLaneViolationTurnovers {
+			gameFile := common.GetGameFilePath(a.inputDir, a.directory, season.Year, season.Type, gameID)
+			gameData, err := common.ReadGameFile(gameFile)
 			if err != nil {
 				fmt.Printf("could not read game file %s: %v\n", gameFile, err)
 				continue
@@ -294,13 +292,7 @@ func (a *Analyzer) processFileForPlayerStats(path string) (PlayerStatsResult, er
 		HasMissingPlayerStats:       false,
 	}
 
-	f, err := os.OpenFile(path, os.O_RDONLY, 0)
-	if err != nil {
-		return result, fmt.Errorf("could not open file %s: %w", path, err)
-	}
-	defer f.Close()
-
-	data, err := io.ReadAll(f)
+	data, err := common.ReadGameFile(path)
 	if err != nil {
 		return result, fmt.Errorf("could not read file %s: %w", path, err)
 	}
@@ -350,7 +342,15 @@ func (a *Analyzer) AnalyzePlayerStats() error {
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() && filepath.Ext(path) == ".json" {
+		// Snapshots of live games are intermediate versions of a game file
+		if d.IsDir() && strings.HasSuffix(d.Name(), ".snapshots") {
+			return filepath.SkipDir
+		}
+		// Run reports are JSON files as well, written by default into the reports directory of the downloads
+		if d.IsDir() && path == filepath.Join(a.inputDir, common.ReportsDirectory) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && common.IsGameFile(d.Name()) {
 			matches = append(matches, path)
 		}
 		return nil
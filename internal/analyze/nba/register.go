@@ -0,0 +1,26 @@
+package nba
+
+import "gamedl/internal/registry"
+
+func init() {
+	registry.RegisterAnalysis(registry.Analysis{
+		Competition:   "nba",
+		Provider:      "sportradar",
+		Name:          "lane-violations",
+		Description:   "Analyzes lane violation events and event type counts",
+		RequiresYears: true,
+		Run: func(opts registry.AnalysisOptions) error {
+			return NewAnalyzer(opts.InputDir, opts.OutputDir, opts.Directory).AnalyzeLaneViolations(opts.Seasons)
+		},
+	})
+	// player-stats searches the input directory recursively instead of by season
+	registry.RegisterAnalysis(registry.Analysis{
+		Competition: "nba",
+		Provider:    "sportradar",
+		Name:        "player-stats",
+		Description: "Finds events with statistics that are missing player information, in every game of the input directory",
+		Run: func(opts registry.AnalysisOptions) error {
+			return NewAnalyzer(opts.InputDir, opts.OutputDir, opts.Directory).AnalyzePlayerStats()
+		},
+	})
+}
@@ -3,7 +3,6 @@ package ncaab
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -16,6 +15,8 @@ import (
 type Analyzer struct {
 	inputDir  string
 	outputDir string
+	// directory is the competition directory of the games, the one SportRadar NCAAB downloads are saved into
+	directory string
 }
 
 type ProcessResultNcaab struct {
@@ -25,9 +26,10 @@ type ProcessResultNcaab struct {
 }
 
 type GameReview struct {
-	Year   int        `json:"year"`
-	ID     string     `json:"id"`
-	Before [][]string `json:"before"`
+	Year       int        `json:"year"`
+	SeasonType string     `json:"season_type"`
+	ID         string     `json:"id"`
+	Before     [][]string `json:"before"`
 }
 
 var NcaabReviewTypes = []string{
@@ -37,10 +39,11 @@ var NcaabReviewTypes = []string{
 	"review",
 }
 
-func NewAnalyzer(inputDir, outputDir string) *Analyzer {
+func NewAnalyzer(inputDir, outputDir, directory string) *Analyzer {
 	return &Analyzer{
 		inputDir:  inputDir,
 		outputDir: outputDir,
+		directory: directory,
 	}
 }
 
@@ -53,13 +56,7 @@ func NewProcessResultNcaab() ProcessResultNcaab {
 
 func (a *Analyzer) processFileNcaab(path string) (ProcessResultNcaab, error) {
 	result := NewProcessResultNcaab()
-	f, err := os.OpenFile(path, os.O_RDONLY, 0)
-	if err != nil {
-		return result, fmt.Errorf("could not open file %s: %w", path, err)
-	}
-	defer f.Close()
-
-	data, err := io.ReadAll(f)
+	data, err := common.ReadGameFile(path)
 	if err != nil {
 		return result, fmt.Errorf("could not read file %s: %w", path, err)
 	}
@@ -90,20 +87,19 @@ func (a *Analyzer) processFileNcaab(path string) (ProcessResultNcaab, error) {
 	return result, nil
 }
 
-func (a *Analyzer) AnalyzeReviewTypes(years []int) error {
+func (a *Analyzer) AnalyzeReviewTypes(seasons []common.Season) error {
 	var errs []error
 	eventsToGames := make(map[string][]*GameReview)
 	eventTypeCount := make(map[string]int)
 
-	for _, year := range years {
-		path := common.GetYearGlobPattern(a.inputDir, "ncaab", year)
-		matches, err := filepath.Glob(path)
+	for _, season := range seasons {
+		matches, err := common.GetYearGameFiles(a.inputDir, a.directory, season.Year, season.Type)
 		if err != nil {
-			fmt.Printf("Error globbing files for year %d: %v\n", year, err)
+			fmt.Printf("Error globbing files for season %v: %v\n", season, err)
 			continue
 		}
 
-		fmt.Printf("year: %d, matches: %v\n", year, len(matches))
+		fmt.Printf("season: %v, matches: %v\n", season, len(matches))
 		for _, match := range matches {
 			result, err := a.processFileNcaab(match)
 			if err != nil {
@@ -116,7 +112,7 @@ func (a *Analyzer) AnalyzeReviewTypes(years []int) error {
 				if slices.Contains(NcaabReviewTypes, eventType) {
 					eventsToGames[eventType] = append(
 						eventsToGames[eventType],
-						&GameReview{Year: year, ID: result.ID, Before: result.BeforeEvent[eventType]},
+						&GameReview{Year: season.Year, SeasonType: season.Type, ID: result.ID, Before: result.BeforeEvent[eventType]},
 					)
 				}
 			}
@@ -153,8 +149,8 @@ func (a *Analyzer) AnalyzeReviewTypes(years []int) error {
 			}
 
 			for _, game := range games {
-				gameFile := common.GetGameFilePath(a.inputDir, "ncaab", game.Year, game.ID)
-				gameData, err := os.ReadFile(gameFile)
+				gameFile := common.GetGameFilePath(a.inputDir, a.directory, game.Year, game.SeasonType, game.ID)
+				gameData, err := common.ReadGameFile(gameFile)
 				if err != nil {
 					fmt.Printf("could not read game file: %v\n", err)
 					continue
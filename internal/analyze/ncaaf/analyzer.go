@@ -3,7 +3,6 @@ package ncaaf
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +14,8 @@ import (
 type Analyzer struct {
 	inputDir  string
 	outputDir string
+	// directory is the competition directory of the games, the one SportRadar NCAAF downloads are saved into
+	directory string
 }
 
 type ProcessResultNcaaf struct {
@@ -24,15 +25,17 @@ type ProcessResultNcaaf struct {
 }
 
 type GameReview struct {
-	Year   int        `json:"year"`
-	ID     string     `json:"id"`
-	Before [][]string `json:"before"`
+	Year       int        `json:"year"`
+	SeasonType string     `json:"season_type"`
+	ID         string     `json:"id"`
+	Before     [][]string `json:"before"`
 }
 
-func NewAnalyzer(inputDir, outputDir string) *Analyzer {
+func NewAnalyzer(inputDir, outputDir, directory string) *Analyzer {
 	return &Analyzer{
 		inputDir:  inputDir,
 		outputDir: outputDir,
+		directory: directory,
 	}
 }
 
@@ -45,13 +48,7 @@ func NewProcessResultNcaaf() ProcessResultNcaaf {
 
 func (a *Analyzer) processFileNcaaf(path string) (ProcessResultNcaaf, error) {
 	result := NewProcessResultNcaaf()
-	f, err := os.OpenFile(path, os.O_RDONLY, 0)
-	if err != nil {
-		return result, fmt.Errorf("could not open file %s: %w", path, err)
-	}
-	defer f.Close()
-
-	data, err := io.ReadAll(f)
+	data, err := common.ReadGameFile(path)
 	if err != nil {
 		return result, fmt.Errorf("could not read file %s: %w", path, err)
 	}
@@ -87,20 +84,19 @@ func (a *Analyzer) processFileNcaaf(path string) (ProcessResultNcaaf, error) {
 	return result, nil
 }
 
-func (a *Analyzer) AnalyzeReviewTypes(years []int) error {
+func (a *Analyzer) AnalyzeReviewTypes(seasons []common.Season) error {
 	var errs []error
 	typesToGames := make(map[string][]GameReview)
 	reviewTypeCount := make(map[string]int)
 
-	for _, year := range years {
-		path := common.GetYearGlobPattern(a.inputDir, "ncaaf", year)
-		matches, err := filepath.Glob(path)
+	for _, season := range seasons {
+		matches, err := common.GetYearGameFiles(a.inputDir, a.directory, season.Year, season.Type)
 		if err != nil {
-			fmt.Printf("Error globbing files for year %d: %v\n", year, err)
+			fmt.Printf("Error globbing files for season %v: %v\n", season, err)
 			continue
 		}
 
-		fmt.Printf("year: %d, matches: %v\n", year, len(matches))
+		fmt.Printf("season: %v, matches: %v\n", season, len(matches))
 		for _, match := range matches {
 			result, err := a.processFileNcaaf(match)
 			if err != nil {
@@ -111,7 +107,7 @@ func (a *Analyzer) AnalyzeReviewTypes(years []int) error {
 			for reviewType, count := range result.Reviews {
 				typesToGames[reviewType] = append(
 					typesToGames[reviewType],
-					GameReview{Year: year, ID: result.ID, Before: result.BeforeReview[reviewType]},
+					GameReview{Year: season.Year, SeasonType: season.Type, ID: result.ID, Before: result.BeforeReview[reviewType]},
 				)
 				reviewTypeCount[reviewType] += count
 			}
@@ -141,8 +137,8 @@ func (a *Analyzer) AnalyzeReviewTypes(years []int) error {
 			lastGames := games[len(games)-nGames:]
 
 			for _, lastGame := range lastGames {
-				gameFile := common.GetGameFilePath(a.inputDir, "ncaaf", lastGame.Year, lastGame.ID)
-				gameData, err := os.ReadFile(gameFile)
+				gameFile := common.GetGameFilePath(a.inputDir, a.directory, lastGame.Year, lastGame.SeasonType, lastGame.ID)
+				gameData, err := common.ReadGameFile(gameFile)
 				if err != nil {
 					fmt.Printf("could not read game file: %v\n", err)
 					continue
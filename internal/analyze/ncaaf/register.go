@@ -0,0 +1,16 @@
+package ncaaf
+
+import "gamedl/internal/registry"
+
+func init() {
+	registry.RegisterAnalysis(registry.Analysis{
+		Competition:   "ncaaf",
+		Provider:      "sportradar",
+		Name:          "review-types",
+		Description:   "Analyzes overturned play reviews and related events",
+		RequiresYears: true,
+		Run: func(opts registry.AnalysisOptions) error {
+			return NewAnalyzer(opts.InputDir, opts.OutputDir, opts.Directory).AnalyzeReviewTypes(opts.Seasons)
+		},
+	})
+}
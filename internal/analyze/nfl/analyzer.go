@@ -3,7 +3,6 @@ package nfl
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -15,6 +14,9 @@ import (
 type Analyzer struct {
 	inputDir  string
 	outputDir string
+	// directory is the competition directory of the games, since BetGenius
+	// NCAAF games share the shape of the NFL ones
+	directory string
 }
 
 type ProcessResultNfl struct {
@@ -26,15 +28,17 @@ type ProcessResultNfl struct {
 }
 
 type GameReview struct {
-	Year   int        `json:"year"`
-	ID     string     `json:"id"`
-	Before [][]string `json:"before"`
+	Year       int        `json:"year"`
+	SeasonType string     `json:"season_type"`
+	ID         string     `json:"id"`
+	Before     [][]string `json:"before"`
 }
 
-func NewAnalyzer(inputDir, outputDir string) *Analyzer {
+func NewAnalyzer(inputDir, outputDir, directory string) *Analyzer {
 	return &Analyzer{
 		inputDir:  inputDir,
 		outputDir: outputDir,
+		directory: directory,
 	}
 }
 
@@ -49,13 +53,7 @@ func NewProcessResultNfl() ProcessResultNfl {
 
 func (a *Analyzer) processFileNfl(path string) (ProcessResultNfl, error) {
 	result := NewProcessResultNfl()
-	f, err := os.OpenFile(path, os.O_RDONLY, 0)
-	if err != nil {
-		return result, fmt.Errorf("could not open file %s: %w", path, err)
-	}
-	defer f.Close()
-
-	data, err := io.ReadAll(f)
+	data, err := common.ReadGameFile(path)
 	if err != nil {
 		return result, fmt.Errorf("could not read file %s: %w", path, err)
 	}
@@ -104,22 +102,21 @@ func (a *Analyzer) processFileNfl(path string) (ProcessResultNfl, error) {
 	return result, nil
 }
 
-func (a *Analyzer) AnalyzeActionTypes(years []int) error {
+func (a *Analyzer) AnalyzeActionTypes(seasons []common.Season) error {
 	var errs []error
 	actionsToGames := make(map[string][]*GameReview)
 	subActionsToGames := make(map[string][]*GameReview)
 	actionTypeCount := make(map[string]int)
 	subActionTypeCount := make(map[string]int)
 
-	for _, year := range years {
-		path := common.GetYearGlobPattern(a.inputDir, "nfl", year)
-		matches, err := filepath.Glob(path)
+	for _, season := range seasons {
+		matches, err := common.GetYearGameFiles(a.inputDir, a.directory, season.Year, season.Type)
 		if err != nil {
-			fmt.Printf("Error globbing files for year %d: %v\n", year, err)
+			fmt.Printf("Error globbing files for season %v: %v\n", season, err)
 			continue
 		}
 
-		fmt.Printf("year: %d, matches: %v\n", year, len(matches))
+		fmt.Printf("season: %v, matches: %v\n", season, len(matches))
 		for _, match := range matches {
 			result, err := a.processFileNfl(match)
 			if err != nil {
@@ -131,7 +128,7 @@ func (a *Analyzer) AnalyzeActionTypes(years []int) error {
 				actionTypeCount[actionType] += count
 				actionsToGames[actionType] = append(
 					actionsToGames[actionType],
-					&GameReview{Year: year, ID: result.ID, Before: result.BeforeAction[actionType]},
+					&GameReview{Year: season.Year, SeasonType: season.Type, ID: result.ID, Before: result.BeforeAction[actionType]},
 				)
 			}
 
@@ -139,7 +136,7 @@ func (a *Analyzer) AnalyzeActionTypes(years []int) error {
 				subActionTypeCount[subActionType] += count
 				subActionsToGames[subActionType] = append(
 					subActionsToGames[subActionType],
-					&GameReview{Year: year, ID: result.ID, Before: result.BeforeAction[subActionType]},
+					&GameReview{Year: season.Year, SeasonType: season.Type, ID: result.ID, Before: result.BeforeAction[subActionType]},
 				)
 			}
 		}
@@ -191,19 +188,18 @@ func (a *Analyzer) AnalyzeActionTypes(years []int) error {
 	return nil
 }
 
-func (a *Analyzer) AnalyzeRecoveriesInConversions(years []int) error {
+func (a *Analyzer) AnalyzeRecoveriesInConversions(seasons []common.Season) error {
 	var errs []error
 	conversionPlaysWithRecoveries := make(map[string][]string, 0)
 
-	for _, year := range years {
-		path := common.GetYearGlobPattern(a.inputDir, "nfl", year)
-		matches, err := filepath.Glob(path)
+	for _, season := range seasons {
+		matches, err := common.GetYearGameFiles(a.inputDir, a.directory, season.Year, season.Type)
 		if err != nil {
-			fmt.Printf("Error globbing files for year %d: %v\n", year, err)
+			fmt.Printf("Error globbing files for season %v: %v\n", season, err)
 			continue
 		}
 
-		fmt.Printf("year: %d, matches: %v\n", year, len(matches))
+		fmt.Printf("season: %v, matches: %v\n", season, len(matches))
 		for _, match := range matches {
 			result, err := a.processFileNfl(match)
 			if err != nil {
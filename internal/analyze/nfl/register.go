@@ -0,0 +1,47 @@
+package nfl
+
+import "gamedl/internal/registry"
+
+func init() {
+	actionTypes := func(opts registry.AnalysisOptions) error {
+		return NewAnalyzer(opts.InputDir, opts.OutputDir, opts.Directory).AnalyzeActionTypes(opts.Seasons)
+	}
+	recoveriesInConversions := func(opts registry.AnalysisOptions) error {
+		return NewAnalyzer(opts.InputDir, opts.OutputDir, opts.Directory).AnalyzeRecoveriesInConversions(opts.Seasons)
+	}
+
+	registry.RegisterAnalysis(registry.Analysis{
+		Competition:   "nfl",
+		Provider:      "betgenius",
+		Name:          "action-types",
+		Description:   "Analyzes play-by-play action types and sequences",
+		RequiresYears: true,
+		Run:           actionTypes,
+	})
+	registry.RegisterAnalysis(registry.Analysis{
+		Competition:   "nfl",
+		Provider:      "betgenius",
+		Name:          "recoveries-in-conversions",
+		Description:   "Lists conversion plays with a recovery",
+		RequiresYears: true,
+		Run:           recoveriesInConversions,
+	})
+
+	// BetGenius NCAAF games have the shape of the NFL ones, so they get the NFL analyses
+	registry.RegisterAnalysis(registry.Analysis{
+		Competition:   "ncaaf",
+		Provider:      "betgenius",
+		Name:          "action-types",
+		Description:   "Same as the NFL analysis, run on BetGenius NCAAF games",
+		RequiresYears: true,
+		Run:           actionTypes,
+	})
+	registry.RegisterAnalysis(registry.Analysis{
+		Competition:   "ncaaf",
+		Provider:      "betgenius",
+		Name:          "recoveries-in-conversions",
+		Description:   "Same as the NFL analysis, run on BetGenius NCAAF games",
+		RequiresYears: true,
+		Run:           recoveriesInConversions,
+	})
+}
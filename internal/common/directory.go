@@ -9,6 +9,9 @@ import (
 	"time"
 )
 
+// ReportsDirectory is the directory of the output directory run reports are written into by default
+const ReportsDirectory = "reports"
+
 // GetGamesDirectoryName returns the directory name for a given competition
 func GetGamesDirectoryName(competition string) string {
 	return competition
@@ -24,20 +27,78 @@ func GetYearDirectoryPath(baseDir, competition string, year int) string {
 	return filepath.Join(GetGamesDirectoryPath(baseDir, competition), strconv.Itoa(year))
 }
 
-// GetGameFilePath returns the full path to a specific game file
-func GetGameFilePath(baseDir, competition string, year int, gameID string) string {
-	return filepath.Join(GetYearDirectoryPath(baseDir, competition, year), gameID+".json")
+// RegularSeason is the season type code of regular season games
+const RegularSeason = "REG"
+
+// Season identifies a season of a competition by its year and season type code (e.g. REG, PST)
+type Season struct {
+	Year int
+	Type string
 }
 
-// GetYearGlobPattern returns the glob pattern for all game files in a specific year
-func GetYearGlobPattern(baseDir, competition string, year int) string {
-	return filepath.Join(GetYearDirectoryPath(baseDir, competition, year), "*.json")
+func (s Season) String() string {
+	return fmt.Sprintf("%d %s", s.Year, s.Type)
 }
 
-// CreateYearDirectory creates the year directory for a competition if it doesn't exist
-func CreateYearDirectory(baseDir, competition string, year int) error {
+// GetSeasonDirectoryPath returns the full path to the directory holding the games of a season type in a year.
+// Regular season games sit directly in the year directory, so datasets downloaded before season types
+// were supported stay valid, while every other season type gets its own subdirectory.
+func GetSeasonDirectoryPath(baseDir, competition string, year int, seasonType string) string {
 	yearDir := GetYearDirectoryPath(baseDir, competition, year)
-	return os.MkdirAll(yearDir, 0o755)
+	if seasonType == "" || seasonType == RegularSeason {
+		return yearDir
+	}
+	return filepath.Join(yearDir, seasonType)
+}
+
+// GetGameFilePath returns the full path to a specific game file
+func GetGameFilePath(baseDir, competition string, year int, seasonType, gameID string) string {
+	return filepath.Join(GetSeasonDirectoryPath(baseDir, competition, year, seasonType), gameID+".json")
+}
+
+// GetSnapshotDirectoryPath returns the full path to the directory holding the live snapshots of a game, next to its game file.
+// It has no .json extension so analyzers globbing for game files don't pick it up.
+func GetSnapshotDirectoryPath(baseDir, competition string, year int, seasonType, gameID string) string {
+	return filepath.Join(GetSeasonDirectoryPath(baseDir, competition, year, seasonType), gameID+".snapshots")
+}
+
+// GetSnapshotFilePath returns the full path to the snapshot of a game payload taken at a given time
+func GetSnapshotFilePath(baseDir, competition string, year int, seasonType, gameID string, takenAt time.Time) string {
+	return filepath.Join(GetSnapshotDirectoryPath(baseDir, competition, year, seasonType, gameID), takenAt.UTC().Format("20060102T150405.000Z")+".json")
+}
+
+// GetYearGlobPatterns returns the glob patterns for all plain and compressed game files of a season type in a specific year
+func GetYearGlobPatterns(baseDir, competition string, year int, seasonType string) []string {
+	seasonDir := GetSeasonDirectoryPath(baseDir, competition, year, seasonType)
+	return []string{
+		filepath.Join(seasonDir, "*.json"),
+		filepath.Join(seasonDir, "*.json"+GzipExtension),
+	}
+}
+
+// GetYearGameFiles returns the paths of all plain and compressed game files of a season type in a specific year
+func GetYearGameFiles(baseDir, competition string, year int, seasonType string) ([]string, error) {
+	var files []string
+	for _, pattern := range GetYearGlobPatterns(baseDir, competition, year, seasonType) {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// GetManifestFilePath returns the full path to the download manifest of a season type in a year.
+// It deliberately has no .json extension so analyzers globbing for game files don't pick it up.
+func GetManifestFilePath(baseDir, competition string, year int, seasonType string) string {
+	return filepath.Join(GetSeasonDirectoryPath(baseDir, competition, year, seasonType), ".manifest")
+}
+
+// CreateYearDirectory creates the directory of a season type in a year for a competition if it doesn't exist
+func CreateYearDirectory(baseDir, competition string, year int, seasonType string) error {
+	seasonDir := GetSeasonDirectoryPath(baseDir, competition, year, seasonType)
+	return os.MkdirAll(seasonDir, 0o755)
 }
 
 // GetAvailableYears returns all available years for a competition by examining the directory structure
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"strings"
+	"time"
+)
+
+// GameFilter selects games by their scheduled date and the teams playing them.
+// Zero values don't filter anything.
+type GameFilter struct {
+	// From is the first day of games to keep
+	From time.Time
+	// To is the last day of games to keep, the whole day included
+	To time.Time
+	// Teams are team identifiers (alias, name or provider id), a game is kept if any of its teams matches one
+	Teams []string
+}
+
+// IsEmpty returns true if the filter keeps every game
+func (f GameFilter) IsEmpty() bool {
+	return f.From.IsZero() && f.To.IsZero() && len(f.Teams) == 0
+}
+
+// HasDates returns true if the filter selects games by their scheduled date
+func (f GameFilter) HasDates() bool {
+	return !f.From.IsZero() || !f.To.IsZero()
+}
+
+// Match reports whether a game scheduled at the given time and played by teams
+// identified by teamIdentifiers passes the filter
+func (f GameFilter) Match(scheduled time.Time, teamIdentifiers ...string) bool {
+	if !f.From.IsZero() && scheduled.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !scheduled.Before(f.To.AddDate(0, 0, 1)) {
+		return false
+	}
+	if len(f.Teams) == 0 {
+		return true
+	}
+	for _, team := range f.Teams {
+		for _, identifier := range teamIdentifiers {
+			if identifier != "" && strings.EqualFold(team, identifier) {
+				return true
+			}
+		}
+	}
+	return false
+}
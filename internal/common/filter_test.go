@@ -0,0 +1,49 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGameFilterMatch(t *testing.T) {
+	day := func(s string) time.Time {
+		date, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return date
+	}
+	at := func(s string) time.Time {
+		date, err := time.ParseInLocation(time.DateTime, s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return date
+	}
+
+	tests := []struct {
+		name      string
+		filter    GameFilter
+		scheduled time.Time
+		teams     []string
+		want      bool
+	}{
+		{"empty filter", GameFilter{}, at("2024-11-18 20:00:00"), nil, true},
+		{"on the from day", GameFilter{From: day("2024-11-18")}, at("2024-11-18 00:00:00"), nil, true},
+		{"before the from day", GameFilter{From: day("2024-11-18")}, at("2024-11-17 23:59:59"), nil, false},
+		{"late on the to day", GameFilter{To: day("2024-11-18")}, at("2024-11-18 23:59:59"), nil, true},
+		{"after the to day", GameFilter{To: day("2024-11-18")}, at("2024-11-19 00:00:00"), nil, false},
+		{"within a single day", GameFilter{From: day("2024-11-18"), To: day("2024-11-18")}, at("2024-11-18 19:30:00"), nil, true},
+		{"team matched, case insensitive", GameFilter{Teams: []string{"bos"}}, at("2024-11-18 20:00:00"), []string{"BOS", "Boston Celtics"}, true},
+		{"team not matched", GameFilter{Teams: []string{"NYK"}}, at("2024-11-18 20:00:00"), []string{"BOS", ""}, false},
+		{"team matched out of the dates", GameFilter{To: day("2024-11-17"), Teams: []string{"BOS"}}, at("2024-11-18 20:00:00"), []string{"BOS"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.scheduled, tt.teams...); got != tt.want {
+				t.Errorf("Match(%v, %v) = %v, want %v", tt.scheduled, tt.teams, got, tt.want)
+			}
+		})
+	}
+}
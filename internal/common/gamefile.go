@@ -0,0 +1,211 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Compression codecs of game files
+const (
+	NoCompression = "none"
+	Gzip          = "gzip"
+)
+
+// GzipExtension is appended to the name of gzip compressed game files (<id>.json.gz)
+const GzipExtension = ".gz"
+
+// Storage tells how game files are written on disk
+type Storage struct {
+	// Compression is the codec game files are compressed with, NoCompression or Gzip
+	Compression string
+	// Compact saves payloads as received from the provider instead of indenting them
+	Compact bool
+}
+
+// GameFile describes a game file as it was saved, so it can be verified later
+type GameFile struct {
+	// Name is the name of the file within its season directory (e.g. <id>.json.gz)
+	Name string `json:"file,omitempty"`
+	// SHA256 is the hex encoded checksum of the file content on disk
+	SHA256 string `json:"sha256,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+	// SourceURL is the provider url the payload was fetched from, without credentials
+	SourceURL string `json:"source_url,omitempty"`
+}
+
+// WriteGameFile saves the raw payload of a game. path is the plain .json path of the game,
+// GzipExtension is appended to it when the storage compresses game files.
+// A game keeps a single file, so the copy of the game in the other format is removed.
+// The file is replaced atomically, so an interrupted write never leaves a truncated game file.
+// Payloads that aren't valid JSON aren't saved, whatever the storage.
+func WriteGameFile(path string, data []byte, storage Storage) (GameFile, error) {
+	return WriteGameStream(path, bytes.NewReader(data), storage)
+}
+
+// WriteGameStream saves the payload of a game read from r like WriteGameFile does, without
+// holding it in memory. The payload is indented, compressed and checksummed while it's written.
+func WriteGameStream(path string, r io.Reader, storage Storage) (GameFile, error) {
+	path = strings.TrimSuffix(path, GzipExtension)
+	stalePath := path + GzipExtension
+	if storage.Compression == Gzip {
+		path, stalePath = stalePath, path
+	}
+
+	hash := sha256.New()
+	var size int64
+	err := writeAtomic(path, 0o644, func(f io.Writer) error {
+		counter := &countingWriter{w: io.MultiWriter(f, hash)}
+		var dst io.WriteCloser = nopCloser{counter}
+		var zw *gzip.Writer
+		if storage.Compression == Gzip {
+			zw = gzip.NewWriter(counter)
+			dst = zw
+		}
+		if !storage.Compact {
+			dst = &chainedCloser{WriteCloser: newIndentWriter(dst), next: dst}
+		}
+
+		if err := copyJSON(dst, r); err != nil {
+			return fmt.Errorf("writing game pbp: %w", err)
+		}
+		if err := dst.Close(); err != nil {
+			return fmt.Errorf("writing game pbp: %w", err)
+		}
+		size = counter.n
+		return nil
+	})
+	if err != nil {
+		return GameFile{}, fmt.Errorf("saving game pbp: %w", err)
+	}
+
+	if err := os.Remove(stalePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return GameFile{}, fmt.Errorf("removing previous game pbp: %w", err)
+	}
+
+	return GameFile{
+		Name:   filepath.Base(path),
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+		Size:   size,
+	}, nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// chainedCloser closes the writer it wraps, then the writer that one writes into
+type chainedCloser struct {
+	io.WriteCloser
+	next io.Closer
+}
+
+func (c *chainedCloser) Close() error {
+	if err := c.WriteCloser.Close(); err != nil {
+		return err
+	}
+	return c.next.Close()
+}
+
+// WriteFileAtomic writes data to a temporary file next to path and renames it into place,
+// so readers see either the previous content or the whole new one.
+// Temporary files are hidden and end with .tmp, so game file globs never pick them up.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeAtomic is WriteFileAtomic for content written by write, which may stream it
+func writeAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+
+	err = write(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// ReadGameFile reads the payload of a game, decompressing it if needed.
+// Given the plain .json path of a game, its compressed file is read when there is no plain one.
+func ReadGameFile(path string) ([]byte, error) {
+	if !strings.HasSuffix(path, GzipExtension) {
+		data, err := os.ReadFile(path)
+		if !errors.Is(err, os.ErrNotExist) {
+			return data, err
+		}
+		path += GzipExtension
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	return io.ReadAll(zr)
+}
+
+// GameFileExists reports whether a game was saved, given the plain .json path of the game,
+// either as a plain or as a compressed file
+func GameFileExists(path string) bool {
+	path = strings.TrimSuffix(path, GzipExtension)
+	for _, candidate := range []string{path, path + GzipExtension} {
+		if _, err := os.Stat(candidate); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGameFile reports whether a file name is the one of a plain or compressed game file
+func IsGameFile(name string) bool {
+	return strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".json"+GzipExtension)
+}
@@ -0,0 +1,184 @@
+package common
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// copyJSON copies the payload read from r into dst, failing unless it's a single valid JSON value.
+// The payload is validated while it's copied, without holding it in memory, so error pages and
+// truncated bodies are never saved as game files.
+func copyJSON(dst io.Writer, r io.Reader) error {
+	dec := json.NewDecoder(io.TeeReader(r, dst))
+	// Numbers are kept as text, so the ones out of the float64 range don't fail the payload
+	dec.UseNumber()
+
+	depth := 0
+	for first := true; first || depth > 0; first = false {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			if first {
+				return errors.New("empty payload")
+			}
+			return errors.New("unexpected end of payload")
+		}
+		if err != nil {
+			return payloadError(err)
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	// Reading up to the end copies the trailing spaces, and fails on anything else
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		if err == nil {
+			return errors.New("unexpected value after the end of the payload")
+		}
+		return payloadError(err)
+	}
+	return nil
+}
+
+// payloadError tells the payload was invalid when err is a JSON syntax error,
+// the other errors being the ones of the reader or the writer
+func payloadError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("invalid JSON payload: %w", err)
+	}
+	return err
+}
+
+// indentWriter indents a JSON payload while it's being written, producing the same output as
+// json.Indent(dst, src, "", "  ") without holding the payload in memory. It fails on unbalanced
+// brackets and incomplete payloads, the payload itself being validated by copyJSON.
+type indentWriter struct {
+	w          *bufio.Writer
+	stack      []byte
+	inString   bool
+	escaped    bool
+	needIndent bool
+	started    bool
+	ended      bool
+}
+
+func newIndentWriter(w io.Writer) *indentWriter {
+	return &indentWriter{w: bufio.NewWriter(w)}
+}
+
+func (iw *indentWriter) Write(p []byte) (int, error) {
+	for i, c := range p {
+		if err := iw.writeByte(c); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+func (iw *indentWriter) writeByte(c byte) error {
+	if iw.inString {
+		switch {
+		case iw.escaped:
+			iw.escaped = false
+		case c == '\\':
+			iw.escaped = true
+		case c == '"':
+			iw.inString = false
+			iw.ended = len(iw.stack) == 0
+		}
+		return iw.w.WriteByte(c)
+	}
+
+	isSpace := c == ' ' || c == '\t' || c == '\r' || c == '\n'
+	if iw.ended {
+		// Trailing spaces are kept, like json.Indent does
+		if isSpace {
+			return iw.w.WriteByte(c)
+		}
+		return fmt.Errorf("unexpected %q after the end of the payload", c)
+	}
+	if isSpace {
+		if iw.started && len(iw.stack) == 0 {
+			iw.ended = true
+		}
+		return nil
+	}
+	iw.started = true
+
+	if iw.needIndent && c != '}' && c != ']' {
+		iw.needIndent = false
+		if err := iw.newline(len(iw.stack)); err != nil {
+			return err
+		}
+	}
+
+	switch c {
+	case '"':
+		iw.inString = true
+		return iw.w.WriteByte(c)
+	case '{', '[':
+		// The indent is delayed so empty objects and arrays are written as {} and []
+		iw.stack = append(iw.stack, c)
+		iw.needIndent = true
+		return iw.w.WriteByte(c)
+	case ',':
+		if len(iw.stack) == 0 {
+			return fmt.Errorf("unexpected %q in payload", c)
+		}
+		if err := iw.w.WriteByte(c); err != nil {
+			return err
+		}
+		return iw.newline(len(iw.stack))
+	case ':':
+		_, err := iw.w.WriteString(": ")
+		return err
+	case '}', ']':
+		open := byte('{')
+		if c == ']' {
+			open = '['
+		}
+		if len(iw.stack) == 0 || iw.stack[len(iw.stack)-1] != open {
+			return fmt.Errorf("unexpected %q in payload", c)
+		}
+		iw.stack = iw.stack[:len(iw.stack)-1]
+		if iw.needIndent {
+			iw.needIndent = false
+		} else if err := iw.newline(len(iw.stack)); err != nil {
+			return err
+		}
+		iw.ended = len(iw.stack) == 0
+		return iw.w.WriteByte(c)
+	default:
+		return iw.w.WriteByte(c)
+	}
+}
+
+func (iw *indentWriter) newline(depth int) error {
+	if err := iw.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	for range depth {
+		if _, err := iw.w.WriteString("  "); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close checks the payload was complete and flushes what's left of it
+func (iw *indentWriter) Close() error {
+	if !iw.started {
+		return errors.New("empty payload")
+	}
+	if iw.inString || len(iw.stack) > 0 {
+		return errors.New("unexpected end of payload")
+	}
+	return iw.w.Flush()
+}
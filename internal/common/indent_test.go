@@ -0,0 +1,120 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIndentWriterMatchesJSONIndent(t *testing.T) {
+	payloads := []string{
+		`{"id":"1","periods":[{"events":[{"type":"shot","made":true},{"type":"foul"}]}]}`,
+		`{"empty":{},"list":[],"nested":[[],{}]}`,
+		`[1,-2.5e3,"a,b:c",null,false]`,
+		`{"escaped":"quote \" and \\ backslash","unicode":"é"}`,
+		` {"spaces" : [ 1 , 2 ] } ` + "\n",
+		`"string"`,
+		`42`,
+		`{"huge":1e400}`,
+	}
+
+	for _, payload := range payloads {
+		var want bytes.Buffer
+		if err := json.Indent(&want, []byte(payload), "", "  "); err != nil {
+			t.Fatalf("json.Indent(%s): %v", payload, err)
+		}
+
+		var got bytes.Buffer
+		iw := newIndentWriter(&got)
+		if err := copyJSON(iw, strings.NewReader(payload)); err != nil {
+			t.Errorf("copyJSON(%s) returned an error: %v", payload, err)
+			continue
+		}
+		if err := iw.Close(); err != nil {
+			t.Errorf("Close after %s returned an error: %v", payload, err)
+			continue
+		}
+		if got.String() != want.String() {
+			t.Errorf("indented %s as\n%s\nwant\n%s", payload, got.String(), want.String())
+		}
+	}
+}
+
+func TestCopyJSONRejectsInvalidPayloads(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{"empty", ""},
+		{"spaces only", "  \n"},
+		{"error page", "<html><body>Bad Gateway</body></html>"},
+		{"truncated object", `{"id":"1","periods":[`},
+		{"truncated string", `{"id":"1`},
+		{"unbalanced brackets", `{"id":[1}`},
+		{"missing colon", `{"id" "1"}`},
+		{"trailing comma", `[1,2,]`},
+		{"bad literal", `{"done":tru}`},
+		{"second value", `{} {}`},
+		{"trailing garbage", `{"id":"1"} oops`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var compact bytes.Buffer
+			if err := copyJSON(&compact, strings.NewReader(tt.payload)); err == nil {
+				t.Errorf("copyJSON(%q) returned no error", tt.payload)
+			}
+		})
+	}
+}
+
+// failingWriter fails once it has been given limit bytes
+type failingWriter struct {
+	limit int
+}
+
+var errWriteFailed = errors.New("disk full")
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > f.limit {
+		n := f.limit
+		f.limit = 0
+		return n, errWriteFailed
+	}
+	f.limit -= len(p)
+	return len(p), nil
+}
+
+func TestIndentWriterPropagatesWriteErrors(t *testing.T) {
+	// Every write error is returned, wherever the payload was when the writer failed
+	payload := `{"a":[1,2,3],"b":{"c":"d"}}`
+	for limit := range 40 {
+		iw := newIndentWriter(&failingWriter{limit: limit})
+		err := copyJSON(iw, strings.NewReader(payload))
+		if err == nil {
+			err = iw.Close()
+		}
+		if !errors.Is(err, errWriteFailed) {
+			t.Errorf("writing into a writer failing after %d bytes returned %v, want the write error", limit, err)
+		}
+	}
+}
+
+func TestWriteGameFileRejectsInvalidPayloads(t *testing.T) {
+	for _, storage := range []Storage{{}, {Compact: true}, {Compression: Gzip, Compact: true}} {
+		path := filepath.Join(t.TempDir(), "1.json")
+		if _, err := WriteGameFile(path, []byte("<html>Service Unavailable</html>"), storage); err == nil {
+			t.Errorf("WriteGameFile with storage %+v saved an invalid payload", storage)
+		}
+		if GameFileExists(path) {
+			t.Errorf("WriteGameFile with storage %+v left a game file", storage)
+		}
+		if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 0 {
+			t.Errorf("WriteGameFile with storage %+v left files %v", storage, files)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ManifestEntry records what was known about a game the last time its payload was saved
+type ManifestEntry struct {
+	ID string `json:"id"`
+	// Status is the game status reported by the provider's schedule when the payload was fetched
+	Status string `json:"status"`
+	// Updated is the provider's change marker for the game in the schedule
+	// (BetGenius lastUpdate, SportRadar scheduled time since their schedules carry no update stamp)
+	Updated string `json:"updated,omitempty"`
+	// Final is true when Status was a final status for the provider, so the payload won't change anymore
+	Final     bool      `json:"final"`
+	FetchedAt time.Time `json:"fetched_at"`
+	// GameFile holds the checksum of the saved file, used by the verify command
+	GameFile
+}
+
+// Manifest keeps track of the games downloaded for a competition season.
+// It is safe for concurrent use.
+type Manifest struct {
+	m     sync.Mutex
+	path  string
+	Games map[string]*ManifestEntry `json:"games"`
+}
+
+// LoadManifest reads the manifest of a season, returning an empty manifest if none exists yet
+func LoadManifest(baseDir, competition string, season Season) (*Manifest, error) {
+	return LoadManifestFile(GetManifestFilePath(baseDir, competition, season.Year, season.Type))
+}
+
+// LoadManifestFile reads a manifest from its path, returning an empty manifest if none exists yet
+func LoadManifestFile(path string) (*Manifest, error) {
+	manifest := &Manifest{
+		path:  path,
+		Games: make(map[string]*ManifestEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("unmarshaling manifest %s: %w", path, err)
+	}
+	if manifest.Games == nil {
+		manifest.Games = make(map[string]*ManifestEntry)
+	}
+
+	return manifest, nil
+}
+
+// NeedsFetch reports whether a game has to be (re)downloaded and why.
+// A game can be skipped only if its file holds a complete payload that was saved
+// while the game was final, and the schedule status and updated marker didn't change since.
+func (mf *Manifest) NeedsFetch(gameID, status, updated, gameFile string) (bool, string) {
+	mf.m.Lock()
+	entry, ok := mf.Games[gameID]
+	mf.m.Unlock()
+
+	if !ok {
+		return true, "not in manifest"
+	}
+	if !entry.Final {
+		return true, "not final"
+	}
+	if entry.Status != status {
+		return true, "status changed"
+	}
+	if !sameUpdated(entry.Updated, updated) {
+		return true, "updated"
+	}
+
+	// Entries recorded before checksums were kept can only be checked for being valid JSON
+	if entry.SHA256 == "" {
+		data, err := ReadGameFile(gameFile)
+		if err != nil {
+			return true, "missing file"
+		}
+		if !json.Valid(data) {
+			return true, "corrupt file"
+		}
+		return false, ""
+	}
+
+	if problem := entry.Check(filepath.Dir(gameFile)); problem != "" {
+		return true, problem + " file"
+	}
+
+	return false, ""
+}
+
+// sameUpdated reports whether two updated markers are the same. The first manifests recorded the
+// SportRadar scheduled times as printed by time.Time.String rather than as RFC3339, so markers that
+// are both times are compared as times, and these games aren't all downloaded again.
+func sameUpdated(recorded, updated string) bool {
+	if recorded == updated {
+		return true
+	}
+	recordedTime, ok := parseUpdated(recorded)
+	if !ok {
+		return false
+	}
+	updatedTime, ok := parseUpdated(updated)
+	return ok && recordedTime.Equal(updatedTime)
+}
+
+// parseUpdated parses an updated marker written as RFC3339 or by time.Time.String
+func parseUpdated(updated string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, updated); err == nil {
+		return t, true
+	}
+	// The zone name printed last can be an offset, which time.Parse doesn't read as a name
+	fields := strings.Fields(updated)
+	if len(fields) < 3 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02 15:04:05.999999999 -0700", strings.Join(fields[:3], " "))
+	return t, err == nil
+}
+
+// Game file problems found by ManifestEntry.Check
+const (
+	FileMissing   = "missing"
+	FileTruncated = "truncated"
+	FileModified  = "modified"
+)
+
+// Check compares the game file of the entry in seasonDir with the checksum recorded when it was saved.
+// It returns FileMissing, FileTruncated or FileModified, or an empty string if the file is intact.
+func (e *ManifestEntry) Check(seasonDir string) string {
+	name := e.Name
+	if name == "" {
+		name = e.ID + ".json"
+	}
+
+	data, err := os.ReadFile(filepath.Join(seasonDir, name))
+	if err != nil {
+		return FileMissing
+	}
+	if int64(len(data)) < e.Size {
+		return FileTruncated
+	}
+	sum := sha256.Sum256(data)
+	if int64(len(data)) != e.Size || hex.EncodeToString(sum[:]) != e.SHA256 {
+		return FileModified
+	}
+
+	return ""
+}
+
+// Record stores the entry of a freshly downloaded game
+func (mf *Manifest) Record(entry ManifestEntry) {
+	mf.m.Lock()
+	defer mf.m.Unlock()
+	mf.Games[entry.ID] = &entry
+}
+
+// Save writes the manifest back to the season directory
+func (mf *Manifest) Save() error {
+	mf.m.Lock()
+	data, err := json.MarshalIndent(mf, "", "  ")
+	mf.m.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+
+	if err := WriteFileAtomic(mf.path, data, 0o644); err != nil {
+		return fmt.Errorf("writing manifest %s: %w", mf.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNeedsFetch(t *testing.T) {
+	const updated = "2024-11-18T02:00:00Z"
+	payload := []byte(`{"id":"1","status":"closed"}`)
+
+	tests := []struct {
+		name       string
+		entry      *ManifestEntry
+		status     string
+		updated    string
+		fileData   []byte
+		wantFetch  bool
+		wantReason string
+	}{
+		{"not in manifest", nil, "closed", updated, payload, true, "not in manifest"},
+		{"not final", &ManifestEntry{Status: "inprogress", Updated: updated}, "inprogress", updated, payload, true, "not final"},
+		{"status changed", &ManifestEntry{Status: "closed", Updated: updated, Final: true}, "complete", updated, payload, true, "status changed"},
+		{"updated", &ManifestEntry{Status: "closed", Updated: updated, Final: true}, "closed", "2024-11-19T02:00:00Z", payload, true, "updated"},
+		{"final and unchanged", &ManifestEntry{Status: "closed", Updated: updated, Final: true}, "closed", updated, payload, false, ""},
+		{"legacy entry, missing file", &ManifestEntry{Status: "closed", Updated: updated, Final: true}, "closed", updated, nil, true, "missing file"},
+		{"legacy entry, corrupt file", &ManifestEntry{Status: "closed", Updated: updated, Final: true}, "closed", updated, []byte(`{"id":`), true, "corrupt file"},
+		{"checksum, intact file", &ManifestEntry{Status: "closed", Updated: updated, Final: true, GameFile: GameFile{SHA256: "set"}}, "closed", updated, payload, false, ""},
+		{"checksum, missing file", &ManifestEntry{Status: "closed", Updated: updated, Final: true, GameFile: GameFile{SHA256: "set"}}, "closed", updated, nil, true, "missing file"},
+		{"checksum, truncated file", &ManifestEntry{Status: "closed", Updated: updated, Final: true, GameFile: GameFile{SHA256: "set"}}, "closed", updated, payload[:10], true, "truncated file"},
+		{"checksum, modified file", &ManifestEntry{Status: "closed", Updated: updated, Final: true, GameFile: GameFile{SHA256: "set"}}, "closed", updated, []byte(`{"id":"1","status":"CLOSED"}`), true, "modified file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			gameFile := filepath.Join(dir, "1.json")
+			manifest, err := LoadManifestFile(filepath.Join(dir, ".manifest"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.entry != nil {
+				entry := *tt.entry
+				entry.ID = "1"
+				if entry.SHA256 != "" {
+					// The checksum is the one of the payload as saved
+					saved, err := WriteGameFile(gameFile, payload, Storage{Compact: true})
+					if err != nil {
+						t.Fatal(err)
+					}
+					entry.GameFile = saved
+				}
+				manifest.Record(entry)
+			}
+			os.Remove(gameFile)
+			if tt.fileData != nil {
+				if err := os.WriteFile(gameFile, tt.fileData, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fetch, reason := manifest.NeedsFetch("1", tt.status, tt.updated, gameFile)
+			if fetch != tt.wantFetch || reason != tt.wantReason {
+				t.Errorf("NeedsFetch = %v, %q, want %v, %q", fetch, reason, tt.wantFetch, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSameUpdated(t *testing.T) {
+	scheduled := time.Date(2024, 11, 18, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		recorded, updated string
+		want              bool
+	}{
+		{"same marker", "2024-11-18T02:00:00Z", "2024-11-18T02:00:00Z", true},
+		{"same time, other zone", "2024-11-17T21:00:00-05:00", "2024-11-18T02:00:00Z", true},
+		{"legacy time.Time.String marker", scheduled.String(), "2024-11-18T02:00:00Z", true},
+		{"legacy marker with an offset zone", scheduled.In(time.FixedZone("", -5*3600)).String(), "2024-11-18T02:00:00Z", true},
+		{"other time", "2024-11-18T03:00:00Z", "2024-11-18T02:00:00Z", false},
+		{"BetGenius markers", "2024-09-08T20:00:00.123Z", "2024-09-08T20:00:00.456Z", false},
+		{"not a time", "v2", "v1", false},
+		{"empty marker", "", "2024-11-18T02:00:00Z", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameUpdated(tt.recorded, tt.updated); got != tt.want {
+				t.Errorf("sameUpdated(%q, %q) = %v, want %v", tt.recorded, tt.updated, got, tt.want)
+			}
+		})
+	}
+}
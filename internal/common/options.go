@@ -0,0 +1,114 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gamedl/lib/web/clients/ratelimit"
+	"gamedl/lib/web/clients/retry"
+	"gamedl/lib/web/clients/usage"
+)
+
+// DownloadOptions holds the settings shared by the downloads of every provider and competition
+type DownloadOptions struct {
+	Seasons []int
+	// SeasonTypes are the season type codes to download (e.g. PRE, REG, PST)
+	SeasonTypes []string
+	// Statuses are the game statuses to download, the provider's default is used when empty
+	Statuses []string
+	// GameIDs are games to download directly, skipping the seasons and schedules walk
+	GameIDs []string
+	// Filter selects the games of the schedules that are downloaded
+	Filter      GameFilter
+	Concurrency int
+	OutputDir   string
+	// Storage tells how game files are written
+	Storage     Storage
+	Incremental bool
+	// DryRun only fetches the seasons and schedules and prints what the download would do
+	DryRun bool
+	// PlanFile is where a dry run writes its plan as JSON, nowhere when empty
+	PlanFile string
+	// Verbose prints a line per downloaded game instead of the progress display
+	Verbose bool
+	// ReportFile is where the JSON report of the run is written, a file of the reports directory when empty
+	ReportFile string
+	Retry      retry.Policy
+	// RateLimits holds the requests per second allowed for each endpoint class, keyed by provider ("sportradar" or "betgenius")
+	RateLimits map[string]ratelimit.Rates
+	// SportRadarKeys are api keys added to the one of the environment, keyed by competition (e.g. nba)
+	SportRadarKeys map[string][]string
+	// SportRadarAPIs select the access level, version and locale of the SportRadar API of each competition,
+	// keyed by competition. The client defaults are used for the fields left empty.
+	SportRadarAPIs map[string]SportRadarAPI
+	// KeyRotation tells how SportRadar requests rotate between the keys of a competition, round-robin when empty
+	KeyRotation string
+	// Usage counts the requests of the clients and refuses the ones over budget, nothing is counted when nil
+	Usage usage.Recorder
+	// HTTP holds the transport settings of the clients of every provider
+	HTTP HTTPOptions
+	// SportRadarBaseURL replaces the host of every SportRadar API when set, e.g. with a local fake server
+	SportRadarBaseURL string
+	// BetGeniusURLs replace the urls of the BetGenius APIs, the fields left empty keep their default
+	BetGeniusURLs BetGeniusURLs
+	// BetGeniusCompetitions are the ids or names of the BetGenius competitions downloaded as a competition
+	// looked up by name (e.g. ncaab), keyed by competition. The default name is looked up when empty.
+	BetGeniusCompetitions map[string]string
+}
+
+// HTTPOptions holds the transport settings of the provider clients. The zero value sends the
+// requests with a default transport, using the proxy of the environment, without timeout.
+type HTTPOptions struct {
+	// Transport sends the requests instead of a default transport, Proxy being ignored when set
+	Transport http.RoundTripper
+	// Timeout limits each attempt of a request, no limit when 0
+	Timeout time.Duration
+	// Proxy is the url of the proxy the requests go through, the one of the environment (HTTPS_PROXY...) when empty
+	Proxy string
+	// UserAgent replaces the User-Agent header of the requests when set
+	UserAgent string
+	// Replay tells Transport serves recorded responses instead of sending the requests,
+	// so the credentials of the providers aren't required
+	Replay bool
+}
+
+// ProxyURL parses the proxy url, returning nil when none is set
+func (o HTTPOptions) ProxyURL() (*url.URL, error) {
+	if o.Proxy == "" {
+		return nil, nil
+	}
+	proxy, err := url.Parse(o.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url %s: %w", o.Proxy, err)
+	}
+	if proxy.Scheme == "" || proxy.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url %s: expected a scheme and a host, e.g. http://proxy:3128", o.Proxy)
+	}
+	return proxy, nil
+}
+
+// BetGeniusURLs replace the urls of the BetGenius APIs
+type BetGeniusURLs struct {
+	// Base replaces the scheme and host of every url, keeping their paths
+	Base string
+	// AuthV1 is the url of the fixtures API login
+	AuthV1 string
+	// OAuth is the url of the match state API token request, query included
+	OAuth string
+	// FixturesV1 is the base url of the fixtures API
+	FixturesV1 string
+	// FixturesV2 is the base url of the match state API
+	FixturesV2 string
+}
+
+// SportRadarAPI selects the SportRadar API of a competition
+type SportRadarAPI struct {
+	// AccessLevel is the access level of the api keys, trial or production
+	AccessLevel string
+	// Version is the version of the API, e.g. v8
+	Version string
+	// Locale is the language of the descriptions of the payloads, e.g. en
+	Locale string
+}
@@ -0,0 +1,172 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"gamedl/internal/registry"
+)
+
+// FieldChange is a field of an event whose value changed between two versions
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// EventChange lists the changes of an event found in both versions
+type EventChange struct {
+	ID      string        `json:"id"`
+	Type    string        `json:"type,omitempty"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// VersionDiff is what the provider changed between two versions of a game payload
+type VersionDiff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Inserted are the events only found in the new version
+	Inserted []Event `json:"inserted"`
+	// Removed are the events only found in the old version
+	Removed []Event `json:"removed"`
+	// Deleted are the ids newly listed by the provider as deleted events
+	Deleted []string `json:"deleted"`
+	// Resequenced are the events whose sequence or parent changed
+	Resequenced []EventChange `json:"resequenced"`
+	// Corrected are the events whose type, clock, points, description or voiding changed
+	Corrected []EventChange `json:"corrected"`
+	// SharedIDs are the ids held by several events of a version, the empty one for events without
+	// id. Their events are matched in order, so their changes may be reported against the wrong event.
+	SharedIDs []string `json:"shared_ids"`
+}
+
+// IsEmpty reports whether nothing changed between both versions
+func (d *VersionDiff) IsEmpty() bool {
+	return len(d.Inserted) == 0 && len(d.Removed) == 0 && len(d.Deleted) == 0 &&
+		len(d.Resequenced) == 0 && len(d.Corrected) == 0
+}
+
+// Report is the change report of successive versions of a game payload
+type Report struct {
+	GameID   string         `json:"game_id,omitempty"`
+	Versions []*VersionDiff `json:"versions"`
+}
+
+// Compare matches the events of two versions of a game payload by id and reports what changed.
+// Events without id or sharing one are matched in the order they appear in each version.
+func Compare(from, to string, older, newer *Payload) *VersionDiff {
+	diff := &VersionDiff{
+		From:        from,
+		To:          to,
+		Inserted:    []Event{},
+		Removed:     []Event{},
+		Deleted:     []string{},
+		Resequenced: []EventChange{},
+		Corrected:   []EventChange{},
+		SharedIDs:   []string{},
+	}
+
+	olderKeys, olderShared := eventKeys(older.Events)
+	newerKeys, newerShared := eventKeys(newer.Events)
+	for _, id := range append(olderShared, newerShared...) {
+		if !slices.Contains(diff.SharedIDs, id) {
+			diff.SharedIDs = append(diff.SharedIDs, id)
+		}
+	}
+
+	olderEvents := make(map[string]Event, len(older.Events))
+	for i, event := range older.Events {
+		olderEvents[olderKeys[i]] = event
+	}
+	newerEvents := make(map[string]Event, len(newer.Events))
+	for i, event := range newer.Events {
+		newerEvents[newerKeys[i]] = event
+	}
+
+	for i, event := range newer.Events {
+		olderEvent, ok := olderEvents[newerKeys[i]]
+		if !ok {
+			diff.Inserted = append(diff.Inserted, event)
+			continue
+		}
+
+		if changes := sequenceChanges(olderEvent, event); len(changes) > 0 {
+			diff.Resequenced = append(diff.Resequenced, EventChange{ID: event.ID, Type: event.Type, Changes: changes})
+		}
+		if changes := contentChanges(olderEvent, event); len(changes) > 0 {
+			diff.Corrected = append(diff.Corrected, EventChange{ID: event.ID, Type: event.Type, Changes: changes})
+		}
+	}
+
+	for i, event := range older.Events {
+		if _, ok := newerEvents[olderKeys[i]]; !ok {
+			diff.Removed = append(diff.Removed, event)
+		}
+	}
+
+	for _, id := range newer.Deleted {
+		if !slices.Contains(older.Deleted, id) {
+			diff.Deleted = append(diff.Deleted, id)
+		}
+	}
+
+	return diff
+}
+
+// eventKeys returns the key events are matched by: their id followed by their rank among the events
+// with the same id, so events without id or sharing one don't overwrite each other.
+// The ids held by several events are returned as well.
+func eventKeys(events []Event) ([]string, []string) {
+	keys := make([]string, len(events))
+	ranks := make(map[string]int, len(events))
+	var shared []string
+	for i, event := range events {
+		rank := ranks[event.ID]
+		ranks[event.ID]++
+		keys[i] = event.ID + "#" + strconv.Itoa(rank)
+		if rank == 1 {
+			shared = append(shared, event.ID)
+		}
+	}
+	return keys, shared
+}
+
+func sequenceChanges(older, newer Event) []FieldChange {
+	var changes []FieldChange
+	changes = appendChange(changes, "parent", older.Parent, newer.Parent)
+	changes = appendChange(changes, "sequence", strconv.FormatFloat(older.Sequence, 'f', -1, 64), strconv.FormatFloat(newer.Sequence, 'f', -1, 64))
+	return changes
+}
+
+func contentChanges(older, newer Event) []FieldChange {
+	var changes []FieldChange
+	changes = appendChange(changes, "type", older.Type, newer.Type)
+	changes = appendChange(changes, "clock", older.Clock, newer.Clock)
+	changes = appendChange(changes, "home_points", strconv.Itoa(older.HomePoints), strconv.Itoa(newer.HomePoints))
+	changes = appendChange(changes, "away_points", strconv.Itoa(older.AwayPoints), strconv.Itoa(newer.AwayPoints))
+	changes = appendChange(changes, "description", older.Description, newer.Description)
+	changes = appendChange(changes, "void", strconv.FormatBool(older.Void), strconv.FormatBool(newer.Void))
+	return changes
+}
+
+func appendChange(changes []FieldChange, field, older, newer string) []FieldChange {
+	if older == newer {
+		return changes
+	}
+	return append(changes, FieldChange{Field: field, Old: older, New: newer})
+}
+
+// formatFor returns the payload format of a provider and competition
+func formatFor(provider, competition string) (format, error) {
+	p, c, err := registry.LookupCompetition(provider, competition)
+	if err != nil {
+		return format{}, err
+	}
+
+	extract, ok := extractors[p.Name+"/"+c.Name]
+	if !ok {
+		return format{}, fmt.Errorf("unsupported competition %s for provider %s", competition, p.Name)
+	}
+	return format{directory: c.Directory, extract: extract}, nil
+}
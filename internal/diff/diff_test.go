@@ -0,0 +1,99 @@
+package diff
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	shot := Event{ID: "1", Parent: "Q1", Sequence: 1, Type: "shot", Clock: "11:40", HomePoints: 2}
+	foul := Event{ID: "2", Parent: "Q1", Sequence: 2, Type: "foul", Clock: "11:20", HomePoints: 2}
+
+	tests := []struct {
+		name            string
+		older, newer    Payload
+		wantInserted    []string
+		wantRemoved     []string
+		wantDeleted     []string
+		wantResequenced []string
+		wantCorrected   []string
+		wantSharedIDs   []string
+	}{
+		{
+			name:  "same events",
+			older: Payload{Events: []Event{shot, foul}},
+			newer: Payload{Events: []Event{shot, foul}},
+		},
+		{
+			name:         "inserted and removed events",
+			older:        Payload{Events: []Event{shot}},
+			newer:        Payload{Events: []Event{foul}},
+			wantInserted: []string{"2"},
+			wantRemoved:  []string{"1"},
+		},
+		{
+			name:            "resequenced and corrected event",
+			older:           Payload{Events: []Event{shot}},
+			newer:           Payload{Events: []Event{{ID: "1", Parent: "Q2", Sequence: 1, Type: "shot", Clock: "11:40", HomePoints: 3}}},
+			wantResequenced: []string{"1"},
+			wantCorrected:   []string{"1"},
+		},
+		{
+			name:        "newly deleted ids",
+			older:       Payload{Deleted: []string{"9"}},
+			newer:       Payload{Deleted: []string{"9", "10"}},
+			wantDeleted: []string{"10"},
+		},
+		{
+			name:          "events without id matched in order",
+			older:         Payload{Events: []Event{{Type: "timeout"}, {Type: "review"}}},
+			newer:         Payload{Events: []Event{{Type: "timeout"}, {Type: "challenge"}, {Type: "review"}}},
+			wantInserted:  []string{""},
+			wantCorrected: []string{""},
+			wantSharedIDs: []string{""},
+		},
+		{
+			name:          "duplicate ids keep their events apart",
+			older:         Payload{Events: []Event{shot, shot}},
+			newer:         Payload{Events: []Event{shot}},
+			wantRemoved:   []string{"1"},
+			wantSharedIDs: []string{"1"},
+		},
+	}
+
+	ids := func(events []Event) []string {
+		var ids []string
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+	changeIDs := func(changes []EventChange) []string {
+		var ids []string
+		for _, change := range changes {
+			ids = append(ids, change.ID)
+		}
+		return ids
+	}
+	check := func(t *testing.T, kind string, got, want []string) {
+		t.Helper()
+		if len(got) == 0 && len(want) == 0 {
+			return
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s = %q, want %q", kind, got, want)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := Compare("a", "b", &tt.older, &tt.newer)
+			check(t, "inserted", ids(diff.Inserted), tt.wantInserted)
+			check(t, "removed", ids(diff.Removed), tt.wantRemoved)
+			check(t, "deleted", diff.Deleted, tt.wantDeleted)
+			check(t, "resequenced", changeIDs(diff.Resequenced), tt.wantResequenced)
+			check(t, "corrected", changeIDs(diff.Corrected), tt.wantCorrected)
+			check(t, "shared ids", diff.SharedIDs, tt.wantSharedIDs)
+		})
+	}
+}
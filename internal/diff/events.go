@@ -0,0 +1,289 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	betgenius2 "gamedl/lib/web/clients/betgenius"
+)
+
+// Event is a play by play event of any provider, reduced to the fields compared across versions
+type Event struct {
+	ID string `json:"id"`
+	// Parent is the period, drive or play holding the event
+	Parent      string  `json:"parent"`
+	Sequence    float64 `json:"sequence"`
+	Type        string  `json:"type,omitempty"`
+	Clock       string  `json:"clock,omitempty"`
+	HomePoints  int     `json:"home_points"`
+	AwayPoints  int     `json:"away_points"`
+	Description string  `json:"description,omitempty"`
+	// Void is true for BetGenius plays voided and actions nullified
+	Void bool `json:"void,omitempty"`
+}
+
+// Payload holds the events of a game payload and the ids the provider listed as deleted
+type Payload struct {
+	Events  []Event
+	Deleted []string
+}
+
+// extractFunc parses a raw game payload into its events
+type extractFunc func(data []byte) (*Payload, error)
+
+// format tells where the games of a provider and competition are downloaded and how to parse them
+type format struct {
+	directory string
+	extract   extractFunc
+}
+
+// extractors are the payload parsers by provider and competition. The directory the games are
+// read from is the one of the competition in the registry.
+var extractors = map[string]extractFunc{
+	"sportradar/nba":   extractSportradarBasketball,
+	"sportradar/ncaab": extractSportradarBasketball,
+	"sportradar/ncaaf": extractSportradarFootball,
+	"sportradar/nfl":   extractSportradarFootball,
+	"betgenius/nfl":    extractNFL,
+	"betgenius/ncaab":  extractBasketball,
+	"betgenius/ncaaf":  extractNFL,
+}
+
+// sportradarBasketballPbp holds the fields of the NBA and NCAAB play by play the events are made of,
+// which both APIs share
+type sportradarBasketballPbp struct {
+	Periods []struct {
+		Type   string `json:"type"`
+		Number int    `json:"number"`
+		Events []struct {
+			ID          string `json:"id"`
+			Sequence    int64  `json:"sequence"`
+			EventType   string `json:"event_type"`
+			Clock       string `json:"clock"`
+			HomePoints  int    `json:"home_points"`
+			AwayPoints  int    `json:"away_points"`
+			Description string `json:"description"`
+		} `json:"events"`
+	} `json:"periods"`
+	DeletedEvents []struct {
+		ID string `json:"id"`
+	} `json:"deleted_events"`
+}
+
+// extractSportradarBasketball returns the events of every period of a SportRadar NBA or NCAAB game
+func extractSportradarBasketball(data []byte) (*Payload, error) {
+	pbp := &sportradarBasketballPbp{}
+	if err := json.Unmarshal(data, pbp); err != nil {
+		return nil, fmt.Errorf("unmarshaling game pbp: %w", err)
+	}
+
+	payload := &Payload{}
+	for _, period := range pbp.Periods {
+		parent := periodName(period.Type, period.Number)
+		for _, event := range period.Events {
+			payload.Events = append(payload.Events, Event{
+				ID:          event.ID,
+				Parent:      parent,
+				Sequence:    float64(event.Sequence),
+				Type:        event.EventType,
+				Clock:       event.Clock,
+				HomePoints:  event.HomePoints,
+				AwayPoints:  event.AwayPoints,
+				Description: event.Description,
+			})
+		}
+	}
+	for _, deleted := range pbp.DeletedEvents {
+		payload.Deleted = append(payload.Deleted, deleted.ID)
+	}
+
+	return payload, nil
+}
+
+// sportradarFootballPbp holds the fields of the NCAAF and NFL play by play the events are made of,
+// which both APIs share
+type sportradarFootballPbp struct {
+	Periods []struct {
+		PeriodType string `json:"period_type"`
+		Number     int    `json:"number"`
+		Pbp        []struct {
+			ID       string  `json:"id"`
+			Sequence float64 `json:"sequence"`
+			Type     string  `json:"type"`
+			Events   []struct {
+				ID          string  `json:"id"`
+				Sequence    float64 `json:"sequence"`
+				Type        string  `json:"type"`
+				PlayType    string  `json:"play_type"`
+				Clock       string  `json:"clock"`
+				HomePoints  int     `json:"home_points"`
+				AwayPoints  int     `json:"away_points"`
+				Description string  `json:"description"`
+			} `json:"events"`
+		} `json:"pbp"`
+	} `json:"periods"`
+}
+
+// extractSportradarFootball returns the drives and other pbp entries of each period of a SportRadar
+// NCAAF or NFL game, and the events within them
+func extractSportradarFootball(data []byte) (*Payload, error) {
+	pbp := &sportradarFootballPbp{}
+	if err := json.Unmarshal(data, pbp); err != nil {
+		return nil, fmt.Errorf("unmarshaling game pbp: %w", err)
+	}
+
+	payload := &Payload{}
+	for _, period := range pbp.Periods {
+		parent := periodName(period.PeriodType, period.Number)
+		for _, entry := range period.Pbp {
+			payload.Events = append(payload.Events, Event{
+				ID:       entry.ID,
+				Parent:   parent,
+				Sequence: entry.Sequence,
+				Type:     entry.Type,
+			})
+			for _, event := range entry.Events {
+				eventType := event.Type
+				if event.PlayType != "" {
+					eventType += "/" + event.PlayType
+				}
+				payload.Events = append(payload.Events, Event{
+					ID:          event.ID,
+					Parent:      entry.ID,
+					Sequence:    event.Sequence,
+					Type:        eventType,
+					Clock:       event.Clock,
+					HomePoints:  event.HomePoints,
+					AwayPoints:  event.AwayPoints,
+					Description: event.Description,
+				})
+			}
+		}
+	}
+
+	return payload, nil
+}
+
+// extractNFL returns the plays of every drive and conversion, and the actions within them
+func extractNFL(data []byte) (*Payload, error) {
+	pbp := &betgenius2.GamePbp{}
+	if err := json.Unmarshal(data, pbp); err != nil {
+		return nil, fmt.Errorf("unmarshaling game pbp: %w", err)
+	}
+
+	payload := &Payload{}
+	addDrives := func(drives []betgenius2.Drive) {
+		for _, drive := range drives {
+			parent := driveName(drive)
+			lastSequence := 0
+			for _, play := range drive.Plays {
+				lastSequence = max(lastSequence, play.Sequence)
+				payload.Events = append(payload.Events, Event{
+					ID:          play.ID,
+					Parent:      parent,
+					Sequence:    float64(play.Sequence),
+					Type:        "play",
+					Clock:       play.StartedAtGameTime,
+					Description: play.Description,
+					Void:        play.IsVoid,
+				})
+				for _, action := range play.Actions {
+					payload.Events = append(payload.Events, Event{
+						ID:       action.ID,
+						Parent:   play.ID,
+						Sequence: float64(action.Sequence),
+						Type:     actionType(action.Type, action.SubType),
+						Void:     action.IsNullified,
+					})
+				}
+			}
+			// Conversion plays have no sequence, they follow the plays of their drive
+			for i, play := range drive.ConversionPlays {
+				payload.Events = append(payload.Events, Event{
+					ID:          play.ID,
+					Parent:      parent,
+					Sequence:    float64(lastSequence + i + 1),
+					Type:        play.Type,
+					Clock:       play.StartedAtGameTime,
+					Description: play.Description,
+					Void:        play.IsVoid,
+				})
+				for _, action := range play.Actions {
+					payload.Events = append(payload.Events, Event{
+						ID:       action.ID,
+						Parent:   play.ID,
+						Sequence: float64(action.Sequence),
+						Type:     actionType(action.Type, action.SubType),
+						Void:     action.IsNullified,
+					})
+				}
+			}
+		}
+	}
+
+	addDrives(pbp.FirstHalf.Drives)
+	addDrives(pbp.SecondHalf.Drives)
+	for _, overtime := range pbp.OvertimePeriods {
+		addDrives(overtime.Drives)
+	}
+
+	return payload, nil
+}
+
+// driveName identifies a drive by its id, so inserting a drive doesn't move the plays of the following
+// ones, or by its first play for payloads without drive ids
+func driveName(drive betgenius2.Drive) string {
+	if drive.ID != "" {
+		return "drive " + drive.ID
+	}
+	if len(drive.Plays) > 0 {
+		return "drive of play " + drive.Plays[0].ID
+	}
+	if len(drive.ConversionPlays) > 0 {
+		return "drive of play " + drive.ConversionPlays[0].ID
+	}
+	return "drive"
+}
+
+// extractBasketball returns the actions of every period of a BetGenius basketball match state
+func extractBasketball(data []byte) (*Payload, error) {
+	pbp := &betgenius2.BasketballGamePbp{}
+	if err := json.Unmarshal(data, pbp); err != nil {
+		return nil, fmt.Errorf("unmarshaling game pbp: %w", err)
+	}
+
+	payload := &Payload{}
+	for _, period := range pbp.Periods {
+		parent := periodName("period", period.Number)
+		if period.IsOvertime {
+			parent = periodName("overtime", period.Number)
+		}
+		for _, action := range period.Actions {
+			payload.Events = append(payload.Events, Event{
+				ID:          action.ID,
+				Parent:      parent,
+				Sequence:    float64(action.Sequence),
+				Type:        actionType(action.Type, &action.SubType),
+				Clock:       action.GameTime,
+				HomePoints:  action.HomeScore,
+				AwayPoints:  action.AwayScore,
+				Description: action.Description,
+				Void:        action.IsVoid,
+			})
+		}
+	}
+
+	return payload, nil
+}
+
+func periodName(periodType string, number int) string {
+	return periodType + " " + strconv.Itoa(number)
+}
+
+func actionType(actionType string, subType *string) string {
+	if subType == nil || *subType == "" {
+		return actionType
+	}
+	return actionType + "/" + *subType
+}
@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the report as markdown, with a section per pair of successive versions
+func (r *Report) Markdown() string {
+	sb := &strings.Builder{}
+	if r.GameID != "" {
+		fmt.Fprintf(sb, "# Changes of game %s\n", r.GameID)
+	} else {
+		fmt.Fprintf(sb, "# Changes\n")
+	}
+
+	for _, version := range r.Versions {
+		fmt.Fprintf(sb, "\n## %s → %s\n\n", version.From, version.To)
+		if version.IsEmpty() {
+			fmt.Fprintf(sb, "No changes.\n")
+			continue
+		}
+
+		fmt.Fprintf(sb, "Inserted: %d, Removed: %d, Deleted: %d, Resequenced: %d, Corrected: %d\n",
+			len(version.Inserted), len(version.Removed), len(version.Deleted), len(version.Resequenced), len(version.Corrected))
+		if len(version.SharedIDs) > 0 {
+			ids := make([]string, len(version.SharedIDs))
+			for i, id := range version.SharedIDs {
+				ids[i] = fmt.Sprintf("%q", id)
+			}
+			fmt.Fprintf(sb, "\nIds held by several events, matched in order: %s\n", strings.Join(ids, ", "))
+		}
+
+		writeEvents(sb, "Inserted", version.Inserted)
+		writeEvents(sb, "Removed", version.Removed)
+
+		if len(version.Deleted) > 0 {
+			fmt.Fprintf(sb, "\n### Deleted events\n\n")
+			for _, id := range version.Deleted {
+				fmt.Fprintf(sb, "- %s\n", id)
+			}
+		}
+
+		writeChanges(sb, "Resequenced", version.Resequenced)
+		writeChanges(sb, "Corrected", version.Corrected)
+	}
+
+	return sb.String()
+}
+
+func writeEvents(sb *strings.Builder, title string, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n### %s\n\n", title)
+	fmt.Fprintf(sb, "| ID | Parent | Sequence | Type | Clock | Home | Away | Description |\n")
+	fmt.Fprintf(sb, "|----|--------|----------|------|-------|------|------|-------------|\n")
+	for _, e := range events {
+		fmt.Fprintf(sb, "| %s | %s | %v | %s | %s | %d | %d | %s |\n",
+			e.ID, escape(e.Parent), e.Sequence, escape(e.Type), e.Clock, e.HomePoints, e.AwayPoints, escape(e.Description))
+	}
+}
+
+func writeChanges(sb *strings.Builder, title string, changes []EventChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n### %s\n\n", title)
+	fmt.Fprintf(sb, "| ID | Type | Field | Old | New |\n")
+	fmt.Fprintf(sb, "|----|------|-------|-----|-----|\n")
+	for _, change := range changes {
+		for _, field := range change.Changes {
+			fmt.Fprintf(sb, "| %s | %s | %s | %s | %s |\n",
+				change.ID, escape(change.Type), field.Field, escape(field.Old), escape(field.New))
+		}
+	}
+}
+
+// escape keeps pipes and line breaks of free text from breaking table rows
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gamedl/internal/common"
+)
+
+type Config struct {
+	Competition string
+	Provider    string
+	InputDir    string
+	// GameID selects the versions of a game found in InputDir: its snapshots, then its game file
+	GameID string
+	// Files are two payloads to compare instead of the versions of GameID
+	Files []string
+	// Format of the report, either json or markdown
+	Format string
+	// Output is the file the report is written to, stdout when empty
+	Output string
+}
+
+func Run(config Config) error {
+	f, err := formatFor(config.Provider, config.Competition)
+	if err != nil {
+		return err
+	}
+
+	files := config.Files
+	if config.GameID != "" {
+		files, err = findVersions(common.GetGamesDirectoryPath(config.InputDir, f.directory), config.GameID)
+		if err != nil {
+			return fmt.Errorf("finding versions of game %s: %w", config.GameID, err)
+		}
+	}
+	if len(files) < 2 {
+		return fmt.Errorf("found %d versions, at least 2 are needed to compare", len(files))
+	}
+
+	report := &Report{GameID: config.GameID}
+	var previous *Payload
+	for i, file := range files {
+		data, err := common.ReadGameFile(file)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", file, err)
+		}
+		payload, err := f.extract(data)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", file, err)
+		}
+
+		if previous != nil {
+			report.Versions = append(report.Versions, Compare(files[i-1], file, previous, payload))
+		}
+		previous = payload
+	}
+
+	var output []byte
+	switch config.Format {
+	case "json":
+		output, err = json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling report: %w", err)
+		}
+	case "markdown", "md", "":
+		output = []byte(report.Markdown())
+	default:
+		return fmt.Errorf("unsupported report format: %s", config.Format)
+	}
+
+	if config.Output == "" {
+		_, err = os.Stdout.Write(output)
+		return err
+	}
+	if err := os.WriteFile(config.Output, output, 0o644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	fmt.Printf("Report written to %s\n", config.Output)
+
+	return nil
+}
+
+// findVersions returns the snapshots of a game in the order they were taken, followed by its game file.
+// The game can be in any season directory of the competition.
+func findVersions(gamesDir, gameID string) ([]string, error) {
+	var snapshots, gameFiles []string
+	for _, seasonPattern := range []string{"*", filepath.Join("*", "*")} {
+		for _, extension := range []string{".json", ".json" + common.GzipExtension} {
+			matches, err := filepath.Glob(filepath.Join(gamesDir, seasonPattern, gameID+".snapshots", "*"+extension))
+			if err != nil {
+				return nil, err
+			}
+			snapshots = append(snapshots, matches...)
+
+			matches, err = filepath.Glob(filepath.Join(gamesDir, seasonPattern, gameID+extension))
+			if err != nil {
+				return nil, err
+			}
+			gameFiles = append(gameFiles, matches...)
+		}
+	}
+
+	// Snapshot file names are UTC timestamps, so sorting them by name sorts them in time
+	sort.Slice(snapshots, func(i, j int) bool {
+		return filepath.Base(snapshots[i]) < filepath.Base(snapshots[j])
+	})
+
+	return append(snapshots, gameFiles...), nil
+}
@@ -4,48 +4,91 @@ import (
 	"fmt"
 	"os"
 
+	"gamedl/internal/common"
 	"gamedl/lib/web/clients/betgenius"
 )
 
-func createBetGeniusClient() (*betgenius.Client, error) {
+// createBetGeniusClient creates a client with the credentials of the environment, which a replay doesn't require
+func createBetGeniusClient(opts common.DownloadOptions) (*betgenius.Client, error) {
 	fixtureKey := os.Getenv("BG_FIXTURE_KEY")
-	if fixtureKey == "" {
+	if fixtureKey == "" && !opts.HTTP.Replay {
 		return nil, fmt.Errorf("BG_FIXTURE_KEY environment variable not set")
 	}
 
 	fixtureUsername := os.Getenv("BG_FIXTURE_USER")
-	if fixtureUsername == "" {
+	if fixtureUsername == "" && !opts.HTTP.Replay {
 		return nil, fmt.Errorf("BG_FIXTURE_USER environment variable not set")
 	}
 
 	fixturePassword := os.Getenv("BG_FIXTURE_PASSWORD")
-	if fixturePassword == "" {
+	if fixturePassword == "" && !opts.HTTP.Replay {
 		return nil, fmt.Errorf("BG_FIXTURE_PASSWORD environment variable not set")
 	}
 
 	statsKey := os.Getenv("BG_STATS_KEY")
-	if statsKey == "" {
+	if statsKey == "" && !opts.HTTP.Replay {
 		return nil, fmt.Errorf("BG_STATS_KEY environment variable not set")
 	}
 
 	statsUsername := os.Getenv("BG_STATS_USER")
-	if statsUsername == "" {
+	if statsUsername == "" && !opts.HTTP.Replay {
 		return nil, fmt.Errorf("BG_STATS_USER environment variable not set")
 	}
 
 	statsPassword := os.Getenv("BG_STATS_PASSWORD")
-	if statsPassword == "" {
+	if statsPassword == "" && !opts.HTTP.Replay {
 		return nil, fmt.Errorf("BG_STATS_PASSWORD environment variable not set")
 	}
 
-	client := betgenius.NewClient(
+	proxy, err := opts.HTTP.ProxyURL()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []betgenius.ClientOption{
 		betgenius.WithStatsKey(statsKey),
 		betgenius.WithFixtureUsername(fixtureUsername),
 		betgenius.WithFixturePassword(fixturePassword),
 		betgenius.WithFixtureKey(fixtureKey),
 		betgenius.WithStatsUsername(statsUsername),
 		betgenius.WithStatsPassword(statsPassword),
-	)
+		betgenius.WithRetryPolicy(opts.Retry),
+		betgenius.WithRateLimits(opts.RateLimits["betgenius"]),
+	}
+	if opts.Usage != nil {
+		options = append(options, betgenius.WithUsage(opts.Usage))
+	}
+	if opts.HTTP.Transport != nil {
+		options = append(options, betgenius.WithTransport(opts.HTTP.Transport))
+	}
+	if opts.HTTP.Timeout > 0 {
+		options = append(options, betgenius.WithTimeout(opts.HTTP.Timeout))
+	}
+	if proxy != nil {
+		options = append(options, betgenius.WithProxy(proxy))
+	}
+	if opts.HTTP.UserAgent != "" {
+		options = append(options, betgenius.WithUserAgent(opts.HTTP.UserAgent))
+	}
+	// The base url goes first, so the urls set one by one aren't rebased
+	urls := opts.BetGeniusURLs
+	if urls.Base != "" {
+		options = append(options, betgenius.WithBaseURL(urls.Base))
+	}
+	if urls.AuthV1 != "" {
+		options = append(options, betgenius.WithAuthV1URL(urls.AuthV1))
+	}
+	if urls.OAuth != "" {
+		options = append(options, betgenius.WithOAuthURL(urls.OAuth))
+	}
+	if urls.FixturesV1 != "" {
+		options = append(options, betgenius.WithFixturesV1URL(urls.FixturesV1))
+	}
+	if urls.FixturesV2 != "" {
+		options = append(options, betgenius.WithFixturesV2URL(urls.FixturesV2))
+	}
+
+	client := betgenius.NewClient(options...)
 
 	return client, nil
 }
@@ -1,8 +1,34 @@
 package betgenius
 
-import "fmt"
+import (
+	"context"
+	"fmt"
 
-func DownloadNCAAB(seasons []int, concurrency int, outputDir string) error {
-	fmt.Println("NCAAB download for BetGenius not yet implemented")
-	return fmt.Errorf("NCAAB download for BetGenius not yet implemented")
+	"gamedl/internal/common"
+	"gamedl/internal/download/engine"
+	betgenius2 "gamedl/lib/web/clients/betgenius"
+)
+
+// ncaabDirectory is the directory BetGenius NCAAB games are saved into,
+// apart from the SportRadar NCAAB games the analyzers read
+const ncaabDirectory = "ncaab-betgenius"
+
+// ncaabCompetition is the name of the BetGenius basketball competition downloaded as NCAAB,
+// unless betgenius.competitions.ncaab is configured
+const ncaabCompetition = "NCAA Division I"
+
+func DownloadNCAAB(ctx context.Context, opts common.DownloadOptions) error {
+	client, err := createBetGeniusClient(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create BetGenius client: %w", err)
+	}
+
+	return engine.Download(ctx, &fixtureSource{
+		directory:     ncaabDirectory,
+		competitionID: configuredCompetition(client, opts, "ncaab", betgenius2.BasketballSportID, ncaabCompetition),
+		getSeasons:    client.GetNcaabSeasons,
+		getFixtures:   client.GetNcaabGamesForSeason,
+		openPbp:       client.OpenNcaabPbp,
+		pbpURL:        client.NcaabPbpURL,
+	}, opts)
 }
@@ -1,8 +1,34 @@
 package betgenius
 
-import "fmt"
+import (
+	"context"
+	"fmt"
 
-func DownloadNCAAF(seasons []int, concurrency int, outputDir string) error {
-	fmt.Println("NCAAF download for BetGenius not yet implemented")
-	return fmt.Errorf("NCAAF download for BetGenius not yet implemented")
+	"gamedl/internal/common"
+	"gamedl/internal/download/engine"
+	betgenius2 "gamedl/lib/web/clients/betgenius"
+)
+
+// ncaafDirectory is the directory BetGenius NCAAF games are saved into,
+// apart from the SportRadar NCAAF games the review-types analysis reads
+const ncaafDirectory = "ncaaf-betgenius"
+
+// ncaafCompetition is the name of the BetGenius american football competition downloaded as NCAAF,
+// unless betgenius.competitions.ncaaf is configured
+const ncaafCompetition = "NCAA Division I FBS"
+
+func DownloadNCAAF(ctx context.Context, opts common.DownloadOptions) error {
+	client, err := createBetGeniusClient(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create BetGenius client: %w", err)
+	}
+
+	return engine.Download(ctx, &fixtureSource{
+		directory:     ncaafDirectory,
+		competitionID: configuredCompetition(client, opts, "ncaaf", betgenius2.AmericanFootballSportID, ncaafCompetition),
+		getSeasons:    client.GetNcaafSeasons,
+		getFixtures:   client.GetNcaafGamesForSeason,
+		openPbp:       client.OpenNcaafPbp,
+		pbpURL:        client.NcaafPbpURL,
+	}, opts)
 }
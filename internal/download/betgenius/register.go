@@ -0,0 +1,19 @@
+package betgenius
+
+import (
+	"gamedl/internal/common"
+	"gamedl/internal/registry"
+)
+
+func init() {
+	// BetGenius seasons aren't split by season type
+	registry.RegisterProvider(registry.Provider{
+		Name:        "betgenius",
+		DisplayName: "BetGenius",
+		Aliases:     []string{"genius", "bg"},
+		SeasonTypes: []string{common.RegularSeason},
+	})
+	registry.RegisterCompetition("betgenius", registry.Competition{Name: "nfl", Directory: nflDirectory, Download: DownloadNFL, Watch: WatchNFL})
+	registry.RegisterCompetition("betgenius", registry.Competition{Name: "ncaab", Directory: ncaabDirectory, Download: DownloadNCAAB})
+	registry.RegisterCompetition("betgenius", registry.Competition{Name: "ncaaf", Directory: ncaafDirectory, Download: DownloadNCAAF})
+}
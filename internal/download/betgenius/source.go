@@ -0,0 +1,133 @@
+package betgenius
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gamedl/internal/common"
+	"gamedl/internal/download/engine"
+	betgenius2 "gamedl/lib/web/clients/betgenius"
+)
+
+// fixtureSource lists and fetches the fixtures of a BetGenius competition for the download engine.
+// The fixtures and match state APIs are the same for every competition, only the competition
+// and the sport of the match state differ.
+type fixtureSource struct {
+	statusPolicy
+	directory string
+	// competitionID returns the id of the competition in the fixtures API
+	competitionID func(ctx context.Context) (string, error)
+	getSeasons    func(ctx context.Context, compId string) (*betgenius2.SeasonsReply, error)
+	getFixtures   func(ctx context.Context, seasonID int) (*betgenius2.GamesOfSeason, error)
+	openPbp       func(ctx context.Context, gameID string) (io.ReadCloser, error)
+	pbpURL        func(gameID string) string
+	// seasonIDs are the ids of the BetGenius seasons of each year
+	seasonIDs map[int][]int
+}
+
+func (s *fixtureSource) Provider() string {
+	return "betgenius"
+}
+
+func (s *fixtureSource) Directory() string {
+	return s.directory
+}
+
+// Seasons returns the selected years. BetGenius seasons aren't split by season type,
+// so all fixtures of a year are filed under the regular season.
+func (s *fixtureSource) Seasons(ctx context.Context, opts common.DownloadOptions) ([]common.Season, error) {
+	competitionID, err := s.competitionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seasonsReply, err := s.getSeasons(ctx, competitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Seasons) > 0 {
+		seasonsReply.FilterYears(opts.Seasons)
+	}
+
+	s.seasonIDs = make(map[int][]int)
+	var seasons []common.Season
+	for _, season := range seasonsReply.Embedded.Seasons {
+		year := season.Year()
+		if _, ok := s.seasonIDs[year]; !ok {
+			seasons = append(seasons, common.Season{Year: year, Type: common.RegularSeason})
+		}
+		s.seasonIDs[year] = append(s.seasonIDs[year], season.ID)
+	}
+	return seasons, nil
+}
+
+func (s *fixtureSource) Games(ctx context.Context, season common.Season) ([]engine.Game, error) {
+	var games []engine.Game
+	for _, seasonID := range s.seasonIDs[season.Year] {
+		schedule, err := s.getFixtures(ctx, seasonID)
+		if err != nil {
+			return nil, err
+		}
+		for _, fixture := range schedule.Embedded.Fixtures {
+			games = append(games, fixtureGame(fixture, season))
+		}
+	}
+	return games, nil
+}
+
+func fixtureGame(fixture *betgenius2.Fixture, season common.Season) engine.Game {
+	// An unparsable start date is left zero, and the game is skipped when filtering by date
+	startTime, _ := fixture.StartTime()
+	return engine.Game{
+		ID:        strconv.Itoa(fixture.ID),
+		Season:    season,
+		Status:    fixture.StatusType,
+		Updated:   fixture.LastUpdate,
+		Scheduled: startTime,
+		Teams:     fixture.CompetitorIdentifiers(),
+	}
+}
+
+func (s *fixtureSource) OpenPbp(ctx context.Context, gameID string) (io.ReadCloser, error) {
+	return s.openPbp(ctx, gameID)
+}
+
+func (s *fixtureSource) PbpURL(gameID string) string {
+	return s.pbpURL(gameID)
+}
+
+// configuredCompetition returns a function finding the id of the BetGenius competition downloaded as a competition:
+// the one configured in opts when it is an id, else the competition of the sport with the configured name, or
+// defaultName when none is configured. Names can change, ids can't, so configuring the id is safer.
+func configuredCompetition(client *betgenius2.Client, opts common.DownloadOptions, competition string, sportID int, defaultName string) func(context.Context) (string, error) {
+	configured := opts.BetGeniusCompetitions[competition]
+	if _, err := strconv.Atoi(configured); err == nil {
+		return func(context.Context) (string, error) { return configured, nil }
+	}
+	name := configured
+	if name == "" {
+		name = defaultName
+	}
+	return competitionByName(client, sportID, name, competition)
+}
+
+// competitionByName returns a function finding the id of a competition of a sport by its name,
+// competition being the one it is downloaded as
+func competitionByName(client *betgenius2.Client, sportID int, name, competition string) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		fmt.Println("Getting competitions data...")
+		competitions, err := client.GetCompetitions(ctx, sportID)
+		if err != nil {
+			return "", fmt.Errorf("getting competitions: %w", err)
+		}
+		found := competitions.Find(name)
+		if found == nil {
+			return "", fmt.Errorf("competition %q not found among the competitions of sport %d %v, set betgenius.competitions.%s to its id or name",
+				name, sportID, competitions.Names(), competition)
+		}
+		return strconv.Itoa(found.ID), nil
+	}
+}
@@ -0,0 +1,39 @@
+package betgenius
+
+import (
+	"slices"
+	"time"
+
+	"gamedl/internal/download/engine"
+)
+
+// defaultStatuses are the fixture statuses downloaded when none are requested.
+// Fixtures that took place keep the scheduled status, unlike postponed or cancelled ones.
+var defaultStatuses = []string{"scheduled"}
+
+// finalStatuses are the fixture statuses a game payload can be final with, once the game is over.
+// Later corrections are caught through the fixture's lastUpdate.
+var finalStatuses = []string{"scheduled"}
+
+// statusPolicy gives the download engine the BetGenius fixture statuses
+type statusPolicy struct{}
+
+func (statusPolicy) DefaultStatuses() []string {
+	return defaultStatuses
+}
+
+// IsFinal reports whether a fixture is over. Fixtures keep the scheduled status before, during and after
+// the game, so a fixture is only final once its live window is past, like the watch tells the live games.
+func (statusPolicy) IsFinal(game engine.Game) bool {
+	if !slices.Contains(finalStatuses, game.Status) || game.Scheduled.IsZero() {
+		return false
+	}
+	return time.Now().After(game.Scheduled.Add(liveWindow))
+}
+
+// finishedMatchStatus is the match status reported by the play by play of a game that is over
+const finishedMatchStatus = "Finished"
+
+// liveWindow is how long after its start a fixture is considered in progress, since fixture
+// statuses don't tell whether a game is being played
+const liveWindow = 6 * time.Hour
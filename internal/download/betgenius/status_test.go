@@ -0,0 +1,54 @@
+package betgenius
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gamedl/internal/common"
+	"gamedl/internal/download/engine"
+)
+
+func TestIncrementalRefetchesFixturesUntilOver(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		status    string
+		scheduled time.Time
+		wantFinal bool
+	}{
+		{"future fixture", "scheduled", now.Add(48 * time.Hour), false},
+		{"fixture in progress", "scheduled", now.Add(-time.Hour), false},
+		{"fixture over", "scheduled", now.Add(-liveWindow - time.Hour), true},
+		{"fixture without start date", "scheduled", time.Time{}, false},
+		{"postponed fixture", "postponed", now.Add(-liveWindow - time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			gameFile := filepath.Join(dir, "1.json")
+			if err := os.WriteFile(gameFile, []byte(`{"matchStatus":"Finished"}`), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			game := engine.Game{ID: "1", Status: tt.status, Updated: "2024-09-08T20:00:00Z", Scheduled: tt.scheduled}
+			final := statusPolicy{}.IsFinal(game)
+			if final != tt.wantFinal {
+				t.Fatalf("IsFinal = %v, want %v", final, tt.wantFinal)
+			}
+
+			manifest, err := common.LoadManifestFile(filepath.Join(dir, ".manifest"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			manifest.Record(common.ManifestEntry{ID: game.ID, Status: game.Status, Updated: game.Updated, Final: final})
+
+			fetch, reason := manifest.NeedsFetch(game.ID, game.Status, game.Updated, gameFile)
+			if fetch == tt.wantFinal {
+				t.Errorf("NeedsFetch = %v (%s), want %v", fetch, reason, !tt.wantFinal)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package betgenius
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gamedl/internal/common"
+	"gamedl/internal/watch"
+	betgenius2 "gamedl/lib/web/clients/betgenius"
+)
+
+// nflWatchSource finds the NFL fixtures that started within the live window in the watched seasons,
+// and the next one to start
+type nflWatchSource struct {
+	client  *betgenius2.Client
+	seasons *betgenius2.SeasonsReply
+	filter  common.GameFilter
+	// startTimes keeps the start time of the fixtures being watched
+	startTimes map[string]time.Time
+}
+
+func (s *nflWatchSource) LiveGames(ctx context.Context) ([]watch.Game, time.Time, error) {
+	seasonToGames, err := gamesPerSeasonNfl(ctx, s.client, s.seasons)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	var live []watch.Game
+	var next time.Time
+	for season, games := range seasonToGames {
+		for _, game := range games {
+			if !slices.Contains(defaultStatuses, game.StatusType) {
+				continue
+			}
+			startTime, err := game.StartTime()
+			if err != nil {
+				// Like the downloads, one fixture without a valid start date doesn't stop the others
+				fmt.Printf("Skipping fixture %d: parsing start date: %v\n", game.ID, err)
+				continue
+			}
+			if !s.filter.Match(startTime, game.CompetitorIdentifiers()...) {
+				continue
+			}
+			if now.Before(startTime) {
+				if next.IsZero() || startTime.Before(next) {
+					next = startTime
+				}
+				continue
+			}
+			if now.After(startTime.Add(liveWindow)) {
+				continue
+			}
+
+			gameID := strconv.Itoa(game.ID)
+			s.startTimes[gameID] = startTime
+			live = append(live, watch.Game{
+				ID:      gameID,
+				Season:  season,
+				Updated: game.LastUpdate,
+			})
+		}
+	}
+	return live, next, nil
+}
+
+func (s *nflWatchSource) FetchPbp(ctx context.Context, game watch.Game) (watch.Payload, error) {
+	gamePbpData, err := s.client.GetNflPbpRaw(ctx, game.ID)
+	if err != nil {
+		return watch.Payload{}, err
+	}
+
+	pbp := &betgenius2.GamePbp{}
+	if err := json.Unmarshal(gamePbpData, pbp); err != nil {
+		return watch.Payload{}, fmt.Errorf("unmarshaling game pbp: %w", err)
+	}
+
+	final := strings.EqualFold(pbp.MatchStatus, finishedMatchStatus)
+	return watch.Payload{
+		Data:      gamePbpData,
+		SourceURL: s.client.NflPbpURL(game.ID),
+		Status:    pbp.MatchStatus,
+		Final:     final,
+		Live:      !final && time.Now().Before(s.startTimes[game.ID].Add(liveWindow)),
+	}, nil
+}
+
+// WatchNFL polls the fixtures in progress of the selected seasons until their play by play reports them finished,
+// and until the last fixture of the filter's window is over. Without selected seasons the two latest ones are watched, since a season spans two years.
+func WatchNFL(ctx context.Context, opts common.DownloadOptions, interval time.Duration) error {
+	client, err := createBetGeniusClient(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create BetGenius client: %w", err)
+	}
+
+	seasonsReply, err := client.GetNflSeasons(ctx, nflCompetitionID)
+	if err != nil {
+		return fmt.Errorf("getting seasons: %w", err)
+	}
+	years := opts.Seasons
+	if len(years) == 0 {
+		years = seasonsReply.Years()
+		slices.Sort(years)
+		if len(years) > 2 {
+			years = years[len(years)-2:]
+		}
+	}
+	seasonsReply.FilterYears(years)
+	if len(seasonsReply.Years()) == 0 {
+		return fmt.Errorf("no seasons to watch")
+	}
+	fmt.Printf("Watching seasons %v every %v...\n", seasonsReply.Years(), interval)
+
+	source := &nflWatchSource{
+		client:     client,
+		seasons:    seasonsReply,
+		filter:     opts.Filter,
+		startTimes: make(map[string]time.Time),
+	}
+	return watch.Poll(ctx, watch.Options{Competition: nflDirectory, Interval: interval, OutputDir: opts.OutputDir, Storage: opts.Storage}, source)
+}
@@ -1,55 +1,44 @@
 package download
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
-	"gamedl/internal/download/betgenius"
-	"gamedl/internal/download/sportradar"
+	"gamedl/internal/common"
+	_ "gamedl/internal/download/betgenius"
+	_ "gamedl/internal/download/sportradar"
+	"gamedl/internal/registry"
 )
 
 type Config struct {
 	Competition string
 	Provider    string
-	Seasons     []int
-	Concurrency int
-	OutputDir   string
+	common.DownloadOptions
 }
 
-func Run(config Config) error {
-	switch config.Provider {
-	case "betgenius", "genius", "bg":
-		return runBetGenius(config)
-	case "sportradar", "sr":
-		return runSportRadar(config)
-	default:
-		return fmt.Errorf("unsupported provider: %s", config.Provider)
+// lookup returns the provider and competition of the config, checking the provider supports its season types
+func lookup(config Config) (*registry.Provider, registry.Competition, error) {
+	provider, competition, err := registry.LookupCompetition(config.Provider, config.Competition)
+	if err != nil {
+		return nil, registry.Competition{}, err
 	}
-}
 
-func runBetGenius(config Config) error {
-	switch config.Competition {
-	case "nfl":
-		return betgenius.DownloadNFL(config.Seasons, config.Concurrency, config.OutputDir)
-	case "ncaab":
-		return betgenius.DownloadNCAAB(config.Seasons, config.Concurrency, config.OutputDir)
-	case "ncaaf":
-		return betgenius.DownloadNCAAF(config.Seasons, config.Concurrency, config.OutputDir)
-	default:
-		return fmt.Errorf("unsupported competition for BetGenius: %s", config.Competition)
+	if len(provider.SeasonTypes) > 0 {
+		for _, seasonType := range config.SeasonTypes {
+			if !slices.Contains(provider.SeasonTypes, seasonType) {
+				return nil, registry.Competition{}, fmt.Errorf("season type %s is not supported for %s, its seasons aren't split by season type", seasonType, provider.DisplayName)
+			}
+		}
 	}
+
+	return provider, competition, nil
 }
 
-func runSportRadar(config Config) error {
-	switch config.Competition {
-	case "nfl":
-		return sportradar.DownloadNFL(config.Seasons, config.Concurrency, config.OutputDir)
-	case "nba":
-		return sportradar.DownloadNBA(config.Seasons, config.Concurrency, config.OutputDir)
-	case "ncaab":
-		return sportradar.DownloadNCAAB(config.Seasons, config.Concurrency, config.OutputDir)
-	case "ncaaf":
-		return sportradar.DownloadNCAAF(config.Seasons, config.Concurrency, config.OutputDir)
-	default:
-		return fmt.Errorf("unsupported competition for SportRadar: %s", config.Competition)
+func Run(ctx context.Context, config Config) error {
+	_, competition, err := lookup(config)
+	if err != nil {
+		return err
 	}
+	return competition.Download(ctx, config.DownloadOptions)
 }
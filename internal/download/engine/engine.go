@@ -0,0 +1,313 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"gamedl/internal/common"
+)
+
+// Game is a game listed in the schedule of a season
+type Game struct {
+	ID     string
+	Season common.Season
+	Status string
+	// Updated changes whenever the provider updates the game. It's compared with
+	// the manifest to tell whether a game changed since it was downloaded.
+	Updated string
+	// Scheduled is the start time of the game, zero when the provider didn't give a valid one
+	Scheduled time.Time
+	// Teams are the names, aliases and ids of the teams of the game, matched by the team filter
+	Teams []string
+}
+
+// Source is a competition of a provider the engine downloads games from
+type Source interface {
+	// Provider is the name of the provider, keying its rate limits in the options
+	Provider() string
+	// Directory is the competition directory games are saved into
+	Directory() string
+	// Seasons returns the seasons selected by the options
+	Seasons(ctx context.Context, opts common.DownloadOptions) ([]common.Season, error)
+	// Games returns the games of a season
+	Games(ctx context.Context, season common.Season) ([]Game, error)
+	// OpenPbp streams the play by play payload of a game. The caller closes the returned body.
+	OpenPbp(ctx context.Context, gameID string) (io.ReadCloser, error)
+	// PbpURL returns the url of the play by play of a game, without credentials
+	PbpURL(gameID string) string
+	// DefaultStatuses are the game statuses downloaded when none are requested
+	DefaultStatuses() []string
+	// IsFinal reports whether the payload of a game as listed in its schedule won't change anymore
+	IsFinal(game Game) bool
+}
+
+// PayloadLocator is implemented by sources whose payloads tell the season of their game.
+// Games requested by id are then fetched straight away instead of being looked up in the schedules.
+type PayloadLocator interface {
+	LocateGame(gameID string, payload io.Reader) (Game, error)
+}
+
+// Download downloads the games of a source selected by the options into the output directory,
+// and records them in the manifests of their seasons. When the context is cancelled, no new game
+// is started, the games in progress are aborted and the games left are listed in a remaining file.
+func Download(ctx context.Context, source Source, opts common.DownloadOptions) error {
+	// A dry run doesn't fetch any play by play, so the requested games are looked up in the schedules
+	if len(opts.GameIDs) > 0 && !opts.DryRun {
+		if locator, ok := source.(PayloadLocator); ok {
+			return downloadByPayload(ctx, source, locator, opts)
+		}
+	}
+
+	fmt.Println("Getting seasons data...")
+	seasons, err := source.Seasons(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("getting seasons: %w", err)
+	}
+
+	fmt.Printf("Getting game ids for seasons %v of types %v...\n", years(seasons), opts.SeasonTypes)
+
+	seasonToGames := make(map[common.Season][]Game, len(seasons))
+	for _, season := range seasons {
+		games, err := source.Games(ctx, season)
+		if err != nil {
+			return fmt.Errorf("getting games: getting game schedule for season %v: %w", season, err)
+		}
+		seasonToGames[season] = append(seasonToGames[season], games...)
+	}
+
+	if len(opts.GameIDs) > 0 {
+		if opts.DryRun {
+			return planByID(source, opts, seasonToGames)
+		}
+		return downloadByID(ctx, source, opts, seasonToGames)
+	}
+
+	statuses := opts.Statuses
+	if len(statuses) == 0 {
+		statuses = source.DefaultStatuses()
+	}
+
+	plan := newPlan(source, opts, statuses)
+	manifests := make(map[common.Season]*common.Manifest)
+	var pending []Game
+	for _, season := range seasons {
+		games := seasonToGames[season]
+		if _, ok := manifests[season]; ok {
+			continue
+		}
+		manifest, err := common.LoadManifest(opts.OutputDir, source.Directory(), season)
+		if err != nil {
+			return fmt.Errorf("loading manifest for season %v: %w", season, err)
+		}
+		manifests[season] = manifest
+
+		seasonPlan := SeasonPlan{Year: season.Year, Type: season.Type, Games: len(games), Statuses: make(map[string]int)}
+		for _, game := range games {
+			if !opts.Filter.IsEmpty() {
+				// A game without a start date can't be placed within the dates of the filter, its teams can
+				if game.Scheduled.IsZero() && opts.Filter.HasDates() {
+					fmt.Printf("Skipping game %s: no valid start date to filter on\n", game.ID)
+					seasonPlan.FilteredOut++
+					continue
+				}
+				if !opts.Filter.Match(game.Scheduled, game.Teams...) {
+					seasonPlan.FilteredOut++
+					continue
+				}
+			}
+			seasonPlan.Statuses[game.Status]++
+			if !slices.Contains(statuses, game.Status) {
+				continue
+			}
+			gameFile := common.GetGameFilePath(opts.OutputDir, source.Directory(), season.Year, season.Type, game.ID)
+			if opts.DryRun && common.GameFileExists(gameFile) {
+				seasonPlan.OnDisk++
+			}
+			reason := ""
+			if opts.Incremental {
+				var fetch bool
+				if fetch, reason = manifest.NeedsFetch(game.ID, game.Status, game.Updated, gameFile); !fetch {
+					seasonPlan.UpToDate++
+					continue
+				}
+			}
+			pending = append(pending, game)
+			plan.add(game, reason)
+			seasonPlan.ToDownload++
+		}
+		plan.Seasons = append(plan.Seasons, seasonPlan)
+
+		printSeasonPlan(seasonPlan, statuses, opts)
+		if seasonPlan.ToDownload > 0 && !opts.DryRun {
+			if err := common.CreateYearDirectory(opts.OutputDir, source.Directory(), season.Year, season.Type); err != nil {
+				return fmt.Errorf("creating directory for season %v: %w", season, err)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return plan.finish(opts)
+	}
+
+	if len(pending) == 0 {
+		fmt.Printf("No games with status %v to download\n", statuses)
+	}
+
+	tasks := make([]task, 0, len(pending))
+	for _, game := range pending {
+		tasks = append(tasks, task{id: game.ID, season: game.Season, save: func(ctx context.Context) (savedGame, error) {
+			return saveGame(ctx, source, opts, game)
+		}})
+	}
+	return run(ctx, source, opts, tasks, manifests)
+}
+
+// downloadByID downloads the requested games, found in the schedules of the selected seasons
+func downloadByID(ctx context.Context, source Source, opts common.DownloadOptions, seasonToGames map[common.Season][]Game) error {
+	games := make(map[string]Game)
+	for _, seasonGames := range seasonToGames {
+		for _, game := range seasonGames {
+			games[game.ID] = game
+		}
+	}
+
+	fmt.Printf("Downloading %d games by id...\n", len(opts.GameIDs))
+	tasks := make([]task, 0, len(opts.GameIDs))
+	for _, gameID := range opts.GameIDs {
+		game, ok := games[gameID]
+		tasks = append(tasks, task{id: gameID, season: game.Season, save: func(ctx context.Context) (savedGame, error) {
+			if !ok {
+				return savedGame{}, fmt.Errorf("game not found in the schedules of the selected seasons")
+			}
+			if err := common.CreateYearDirectory(opts.OutputDir, source.Directory(), game.Season.Year, game.Season.Type); err != nil {
+				return savedGame{season: game.Season}, fmt.Errorf("creating directory for season %v: %w", game.Season, err)
+			}
+			return saveGame(ctx, source, opts, game)
+		}})
+	}
+	return run(ctx, source, opts, tasks, make(map[common.Season]*common.Manifest))
+}
+
+// downloadByPayload downloads the requested games into the directory of the season their payload belongs to
+func downloadByPayload(ctx context.Context, source Source, locator PayloadLocator, opts common.DownloadOptions) error {
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	fmt.Printf("Downloading %d games by id...\n", len(opts.GameIDs))
+	tasks := make([]task, 0, len(opts.GameIDs))
+	for _, gameID := range opts.GameIDs {
+		tasks = append(tasks, task{id: gameID, save: func(ctx context.Context) (savedGame, error) {
+			return saveLocatedGame(ctx, source, locator, opts, gameID)
+		}})
+	}
+	return run(ctx, source, opts, tasks, make(map[common.Season]*common.Manifest))
+}
+
+// saveGame streams the payload of a game into its game file
+func saveGame(ctx context.Context, source Source, opts common.DownloadOptions, game Game) (savedGame, error) {
+	saved := savedGame{season: game.Season}
+	body, err := source.OpenPbp(ctx, game.ID)
+	if err != nil {
+		return saved, fmt.Errorf("fetching game pbp: %w", err)
+	}
+	defer body.Close()
+
+	received := &countingReader{r: body}
+	path := common.GetGameFilePath(opts.OutputDir, source.Directory(), game.Season.Year, game.Season.Type, game.ID)
+	gameFile, err := common.WriteGameStream(path, received, opts.Storage)
+	saved.bytes = received.n
+	if err != nil {
+		return saved, err
+	}
+	gameFile.SourceURL = source.PbpURL(game.ID)
+
+	saved.entry = manifestEntry(source, game, gameFile)
+	return saved, nil
+}
+
+// saveLocatedGame downloads a game whose season is only known from its payload. The payload
+// is kept in a hidden temporary file of the output directory until its season is found.
+func saveLocatedGame(ctx context.Context, source Source, locator PayloadLocator, opts common.DownloadOptions, gameID string) (savedGame, error) {
+	saved := savedGame{}
+	body, err := source.OpenPbp(ctx, gameID)
+	if err != nil {
+		return saved, fmt.Errorf("fetching game pbp: %w", err)
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(opts.OutputDir, "."+gameID+".*.tmp")
+	if err != nil {
+		return saved, fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	saved.bytes, err = io.Copy(tmp, body)
+	if err != nil {
+		return saved, fmt.Errorf("fetching game pbp: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return saved, fmt.Errorf("rewinding game pbp: %w", err)
+	}
+
+	game, err := locator.LocateGame(gameID, tmp)
+	if err != nil {
+		return saved, err
+	}
+	saved.season = game.Season
+	if err := common.CreateYearDirectory(opts.OutputDir, source.Directory(), game.Season.Year, game.Season.Type); err != nil {
+		return saved, fmt.Errorf("creating directory for season %v: %w", game.Season, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return saved, fmt.Errorf("rewinding game pbp: %w", err)
+	}
+
+	path := common.GetGameFilePath(opts.OutputDir, source.Directory(), game.Season.Year, game.Season.Type, gameID)
+	gameFile, err := common.WriteGameStream(path, tmp, opts.Storage)
+	if err != nil {
+		return saved, err
+	}
+	gameFile.SourceURL = source.PbpURL(gameID)
+
+	saved.entry = manifestEntry(source, game, gameFile)
+	return saved, nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func manifestEntry(source Source, game Game, gameFile common.GameFile) common.ManifestEntry {
+	return common.ManifestEntry{
+		ID:        game.ID,
+		Status:    game.Status,
+		Updated:   game.Updated,
+		Final:     source.IsFinal(game),
+		FetchedAt: time.Now(),
+		GameFile:  gameFile,
+	}
+}
+
+// years returns the distinct years of the seasons, in order
+func years(seasons []common.Season) []int {
+	var years []int
+	for _, season := range seasons {
+		if !slices.Contains(years, season.Year) {
+			years = append(years, season.Year)
+		}
+	}
+	return years
+}
@@ -0,0 +1,250 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"gamedl/internal/common"
+	"gamedl/lib/web/clients/usage"
+)
+
+var testSeason = common.Season{Year: 2024, Type: common.RegularSeason}
+
+// fakeSource serves the games of a single season. Games without a payload fail to download,
+// and games with an error fail with it.
+type fakeSource struct {
+	games    []Game
+	payloads map[string]string
+	errors   map[string]error
+
+	m      sync.Mutex
+	opened []string
+}
+
+func (s *fakeSource) Provider() string  { return "fake" }
+func (s *fakeSource) Directory() string { return "fake" }
+
+func (s *fakeSource) Seasons(context.Context, common.DownloadOptions) ([]common.Season, error) {
+	return []common.Season{testSeason}, nil
+}
+
+func (s *fakeSource) Games(context.Context, common.Season) ([]Game, error) {
+	return s.games, nil
+}
+
+func (s *fakeSource) OpenPbp(_ context.Context, gameID string) (io.ReadCloser, error) {
+	s.m.Lock()
+	s.opened = append(s.opened, gameID)
+	s.m.Unlock()
+
+	if err := s.errors[gameID]; err != nil {
+		return nil, err
+	}
+	payload, ok := s.payloads[gameID]
+	if !ok {
+		return nil, fmt.Errorf("bad status code: 500")
+	}
+	return io.NopCloser(strings.NewReader(payload)), nil
+}
+
+func (s *fakeSource) PbpURL(gameID string) string { return "https://provider.test/games/" + gameID }
+func (s *fakeSource) DefaultStatuses() []string   { return []string{"closed", "inprogress"} }
+func (s *fakeSource) IsFinal(game Game) bool      { return game.Status == "closed" }
+
+// openedGames returns the games whose play by play was fetched since the last call, sorted
+func (s *fakeSource) openedGames() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	opened := s.opened
+	s.opened = nil
+	slices.Sort(opened)
+	return opened
+}
+
+func testOptions(t *testing.T) common.DownloadOptions {
+	t.Helper()
+	return common.DownloadOptions{
+		SeasonTypes: []string{common.RegularSeason},
+		Concurrency: 2,
+		OutputDir:   t.TempDir(),
+		Verbose:     true,
+	}
+}
+
+func game(id, status, updated string) Game {
+	return Game{ID: id, Season: testSeason, Status: status, Updated: updated}
+}
+
+func TestIncrementalDownload(t *testing.T) {
+	source := &fakeSource{
+		games: []Game{game("1", "closed", "a"), game("2", "closed", "a"), game("3", "inprogress", "a")},
+		payloads: map[string]string{
+			"1": `{"id":"1"}`,
+			"2": `{"id":"2"}`,
+			"3": `{"id":"3"}`,
+		},
+	}
+	opts := testOptions(t)
+	if err := Download(context.Background(), source, opts); err != nil {
+		t.Fatalf("first download: %v", err)
+	}
+	if got := source.openedGames(); !slices.Equal(got, []string{"1", "2", "3"}) {
+		t.Fatalf("first download fetched %v, want every game", got)
+	}
+
+	// Each case downloads into the output of the previous ones
+	tests := []struct {
+		name  string
+		games []Game
+		// prepare alters the output directory before the download
+		prepare func(t *testing.T)
+		want    []string
+	}{
+		{
+			name:  "only games not final",
+			games: source.games,
+			want:  []string{"3"},
+		},
+		{
+			name:  "updated and new games",
+			games: []Game{game("1", "closed", "b"), game("2", "closed", "a"), game("4", "closed", "a")},
+			want:  []string{"1", "4"},
+		},
+		{
+			name:  "modified game file",
+			games: []Game{game("1", "closed", "b"), game("2", "closed", "a")},
+			prepare: func(t *testing.T) {
+				path := common.GetGameFilePath(opts.OutputDir, "fake", testSeason.Year, testSeason.Type, "2")
+				if err := os.WriteFile(path, []byte(`{"id":"X"}`), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"2"},
+		},
+	}
+
+	source.payloads["4"] = `{"id":"4"}`
+	opts.Incremental = true
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source.games = tt.games
+			if tt.prepare != nil {
+				tt.prepare(t)
+			}
+			if err := Download(context.Background(), source, opts); err != nil {
+				t.Fatalf("incremental download: %v", err)
+			}
+			if got := source.openedGames(); !slices.Equal(got, tt.want) {
+				t.Errorf("incremental download fetched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportAndRetryFailed(t *testing.T) {
+	source := &fakeSource{
+		games:    []Game{game("1", "closed", "a"), game("2", "closed", "a")},
+		payloads: map[string]string{"1": `{"id":"1"}`},
+	}
+	opts := testOptions(t)
+	opts.ReportFile = filepath.Join(opts.OutputDir, "run.json")
+	if err := Download(context.Background(), source, opts); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+
+	report, err := ReadReport(opts.ReportFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Provider != "fake" || report.Directory != "fake" || report.Interrupted {
+		t.Errorf("report of %s/%s interrupted %v, want a complete fake/fake run", report.Provider, report.Directory, report.Interrupted)
+	}
+	if report.Summary != (ReportSummary{Total: 2, Downloaded: 1, Failed: 1}) {
+		t.Errorf("report summary = %+v, want 1 of 2 games downloaded and 1 failed", report.Summary)
+	}
+	for _, game := range report.Games {
+		switch game.ID {
+		case "1":
+			if game.Status != GameDownloaded || !common.GameFileExists(game.Path) {
+				t.Errorf("game 1 reported %+v, want downloaded with its game file", game)
+			}
+		case "2":
+			if game.Status != GameFailed || !strings.Contains(game.Error, "500") || game.Year != testSeason.Year {
+				t.Errorf("game 2 reported %+v, want failed with its error and season", game)
+			}
+		}
+	}
+
+	failed := report.FailedGames()
+	if len(failed) != 1 || failed[0].ID != "2" {
+		t.Fatalf("FailedGames() = %+v, want game 2", failed)
+	}
+
+	// Retrying the failed games downloads them by id, into the manifest of their season
+	source.openedGames()
+	source.payloads["2"] = `{"id":"2"}`
+	opts.GameIDs = []string{failed[0].ID}
+	opts.ReportFile = filepath.Join(opts.OutputDir, "retry.json")
+	if err := Download(context.Background(), source, opts); err != nil {
+		t.Fatalf("retrying failed games: %v", err)
+	}
+	if got := source.openedGames(); !slices.Equal(got, []string{"2"}) {
+		t.Errorf("retry fetched %v, want the failed game only", got)
+	}
+	retried, err := ReadReport(opts.ReportFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.Summary != (ReportSummary{Total: 1, Downloaded: 1}) || len(retried.FailedGames()) != 0 {
+		t.Errorf("retry report summary = %+v, want the game downloaded", retried.Summary)
+	}
+	manifest, err := common.LoadManifest(opts.OutputDir, "fake", testSeason)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := manifest.Games["2"]; entry == nil || !entry.Final || entry.SourceURL != source.PbpURL("2") {
+		t.Errorf("manifest entry of game 2 = %+v, want it recorded final with its url", entry)
+	}
+}
+
+func TestReportOfRunOutOfCalls(t *testing.T) {
+	budgetErr := &usage.BudgetError{Reason: "the run already made its 1 calls"}
+	source := &fakeSource{
+		games:    []Game{game("1", "closed", "a"), game("2", "closed", "a"), game("3", "closed", "a")},
+		payloads: map[string]string{"1": `{"id":"1"}`},
+		errors:   map[string]error{"2": budgetErr, "3": budgetErr},
+	}
+	opts := testOptions(t)
+	opts.Concurrency = 1
+	opts.ReportFile = filepath.Join(opts.OutputDir, "run.json")
+
+	err := Download(context.Background(), source, opts)
+	if !errors.Is(err, usage.ErrBudgetExceeded) {
+		t.Fatalf("download returned %v, want an error wrapping usage.ErrBudgetExceeded", err)
+	}
+
+	report, err := ReadReport(opts.ReportFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Interrupted || report.Summary != (ReportSummary{Total: 3, Downloaded: 1, Remaining: 2}) {
+		t.Errorf("report interrupted %v with summary %+v, want 1 game downloaded and 2 remaining", report.Interrupted, report.Summary)
+	}
+	remaining, err := os.ReadFile(filepath.Join(opts.OutputDir, "remaining-fake.txt"))
+	if err != nil {
+		t.Fatalf("reading remaining games: %v", err)
+	}
+	for _, id := range []string{"2", "3"} {
+		if !strings.Contains(string(remaining), id) {
+			t.Errorf("remaining games %q miss game %s", remaining, id)
+		}
+	}
+}
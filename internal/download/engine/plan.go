@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"gamedl/internal/common"
+	"gamedl/lib/web/clients/ratelimit"
+)
+
+// Plan is what a download would do, computed by a dry run from the seasons and schedules only
+type Plan struct {
+	Provider  string    `json:"provider"`
+	Directory string    `json:"directory"`
+	CreatedAt time.Time `json:"created_at"`
+	Statuses  []string  `json:"statuses,omitempty"`
+	// Seasons sums up the schedule of every selected season, empty when games are requested by id
+	Seasons []SeasonPlan `json:"seasons,omitempty"`
+	// PbpCalls is the number of play by play requests the download would make, retries aside
+	PbpCalls int `json:"pbp_calls"`
+	// PbpRate is the configured play by play requests per second, 0 when unlimited
+	PbpRate float64 `json:"pbp_rate,omitempty"`
+	// EstimatedDuration is the time the play by play requests take at PbpRate, empty when unlimited
+	EstimatedDuration string `json:"estimated_duration,omitempty"`
+	// Games are the games the download would fetch. A plan file can be given to --game-ids-file.
+	Games []PlannedGame `json:"games"`
+}
+
+// SeasonPlan sums up the schedule of a season
+type SeasonPlan struct {
+	Year int    `json:"year"`
+	Type string `json:"type"`
+	// Games is the number of games in the schedule, Statuses counts the ones left by the filter by status
+	Games       int            `json:"games"`
+	Statuses    map[string]int `json:"statuses"`
+	FilteredOut int            `json:"filtered_out,omitempty"`
+	// OnDisk counts the games of the selected statuses that already have a game file
+	OnDisk     int `json:"on_disk"`
+	UpToDate   int `json:"up_to_date,omitempty"`
+	ToDownload int `json:"to_download"`
+}
+
+// PlannedGame is a game the download would fetch
+type PlannedGame struct {
+	ID     string `json:"id"`
+	Year   int    `json:"year,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Status string `json:"status,omitempty"`
+	// Reason tells why an incremental download would fetch the game again, or why it can't be found
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReadPlanGameIDs returns the ids of the games of a plan written by a dry run
+func ReadPlanGameIDs(data []byte) ([]string, error) {
+	plan := Plan{}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("unmarshaling plan: %w", err)
+	}
+	gameIDs := make([]string, 0, len(plan.Games))
+	for _, game := range plan.Games {
+		gameIDs = append(gameIDs, game.ID)
+	}
+	return gameIDs, nil
+}
+
+func newPlan(source Source, opts common.DownloadOptions, statuses []string) *Plan {
+	return &Plan{
+		Provider:  source.Provider(),
+		Directory: source.Directory(),
+		CreatedAt: time.Now().UTC(),
+		Statuses:  statuses,
+		PbpRate:   opts.RateLimits[source.Provider()][ratelimit.Pbp],
+		Games:     []PlannedGame{},
+	}
+}
+
+func (p *Plan) add(game Game, reason string) {
+	p.Games = append(p.Games, PlannedGame{
+		ID:     game.ID,
+		Year:   game.Season.Year,
+		Type:   game.Season.Type,
+		Status: game.Status,
+		Reason: reason,
+	})
+	p.PbpCalls++
+}
+
+// finish prints the totals of the plan and writes it to the plan file when one is set
+func (p *Plan) finish(opts common.DownloadOptions) error {
+	fmt.Println("Dry run, no game downloaded")
+	fmt.Printf("Play by play requests: %d\n", p.PbpCalls)
+	if p.PbpRate > 0 {
+		estimate := time.Duration(float64(p.PbpCalls) / p.PbpRate * float64(time.Second)).Round(time.Second)
+		p.EstimatedDuration = estimate.String()
+		fmt.Printf("Estimated duration: %v at %g requests per second, retries aside\n", estimate, p.PbpRate)
+	} else {
+		fmt.Println("Estimated duration: unknown, no play by play rate limit is configured")
+	}
+
+	if opts.PlanFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling plan: %w", err)
+	}
+	if err := common.WriteFileAtomic(opts.PlanFile, data, 0o644); err != nil {
+		return fmt.Errorf("writing plan: %w", err)
+	}
+	fmt.Printf("Plan written to %s\n", opts.PlanFile)
+	return nil
+}
+
+// printSeasonPlan prints the schedule summary of a season
+func printSeasonPlan(seasonPlan SeasonPlan, statuses []string, opts common.DownloadOptions) {
+	fmt.Printf("Season: %v, Games: %v\n", common.Season{Year: seasonPlan.Year, Type: seasonPlan.Type}, seasonPlan.Games)
+
+	names := make([]string, 0, len(seasonPlan.Statuses))
+	for status := range seasonPlan.Statuses {
+		names = append(names, status)
+	}
+	sort.Strings(names)
+	for _, status := range names {
+		skipped := ""
+		if !slices.Contains(statuses, status) {
+			skipped = " (skipped)"
+		}
+		fmt.Printf("  Status: %v, Count: %v%s\n", status, seasonPlan.Statuses[status], skipped)
+	}
+	if !opts.Filter.IsEmpty() {
+		fmt.Printf("  Filtered out: %v\n", seasonPlan.FilteredOut)
+	}
+	if opts.DryRun {
+		fmt.Printf("  On disk: %v\n", seasonPlan.OnDisk)
+	}
+	if opts.Incremental {
+		fmt.Printf("  Up to date: %v\n", seasonPlan.UpToDate)
+	}
+	if opts.DryRun {
+		fmt.Printf("  To download: %v\n", seasonPlan.ToDownload)
+	}
+}
+
+// planByID plans the download of the requested games, looked up in the schedules of the selected seasons.
+// Sources reading the season of a game from its payload still fetch the games missing from the schedules.
+func planByID(source Source, opts common.DownloadOptions, seasonToGames map[common.Season][]Game) error {
+	games := make(map[string]Game)
+	for _, seasonGames := range seasonToGames {
+		for _, game := range seasonGames {
+			games[game.ID] = game
+		}
+	}
+	_, locates := source.(PayloadLocator)
+
+	plan := newPlan(source, opts, nil)
+	found := 0
+	for _, gameID := range opts.GameIDs {
+		game, ok := games[gameID]
+		switch {
+		case ok:
+			found++
+			plan.add(game, "")
+		case locates:
+			plan.add(Game{ID: gameID}, "not in the schedules of the selected seasons, located from its payload")
+		default:
+			fmt.Printf("Game %s not found in the schedules of the selected seasons, it would fail\n", gameID)
+		}
+	}
+	fmt.Printf("Games requested by id: %d, found in the schedules: %d\n", len(opts.GameIDs), found)
+	return plan.finish(opts)
+}
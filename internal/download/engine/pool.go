@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gamedl/internal/common"
+	"gamedl/lib/web/clients/retry"
+	"gamedl/lib/web/clients/usage"
+)
+
+// task downloads one game with the context it's given
+type task struct {
+	id     string
+	season common.Season
+	save   func(ctx context.Context) (savedGame, error)
+}
+
+// savedGame is what a task saved. The season is set as soon as it's known, even when the task fails.
+type savedGame struct {
+	season common.Season
+	entry  common.ManifestEntry
+	// bytes is the size of the payload received from the provider
+	bytes int64
+}
+
+// taskReport is the outcome of a task
+type taskReport struct {
+	id string
+	// season is the season of the task, zero when it's only known from the payload of the game
+	season   common.Season
+	saved    savedGame
+	err      error
+	stats    retry.Stats
+	duration time.Duration
+}
+
+// run downloads the games of the tasks with opts.Concurrency workers, printing the progress as
+// games complete. Downloaded games are recorded in the manifest of their season, which is
+// loaded unless given, and the manifests are saved once every task is done. The outcome of
+// every game is written to the report of the run.
+//
+// When the context is cancelled, the tasks not started yet are dropped and the ones in progress
+// abort. When the calls left are used up (a call budget is exhausted, or every api key ran out of
+// quota), the tasks not started yet are dropped too and the ones in progress complete. The games left are then written to a remaining file that --game-ids-file
+// can resume from.
+func run(ctx context.Context, source Source, opts common.DownloadOptions, tasks []task, manifests map[common.Season]*common.Manifest) error {
+	directory := source.Directory()
+	totalGames := len(tasks)
+	taskChannel := make(chan task)
+	reportChannel := make(chan taskReport)
+	report := newReport(source, totalGames)
+	// stopped is closed when a game can't be downloaded for lack of calls, to stop feeding new tasks
+	stopped := make(chan struct{})
+	var outOfCallsErr error
+
+	wg := sync.WaitGroup{}
+	for range max(opts.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range taskChannel {
+				result := taskReport{id: t.id, season: t.season}
+				start := time.Now()
+				result.saved, result.err = t.save(retry.WithStats(ctx, &result.stats))
+				result.duration = time.Since(start)
+				if result.saved.season == (common.Season{}) {
+					result.saved.season = t.season
+				}
+				reportChannel <- result
+			}
+		}()
+	}
+
+	go func() {
+	feed:
+		for _, t := range tasks {
+			select {
+			case taskChannel <- t:
+			case <-ctx.Done():
+				break feed
+			case <-stopped:
+				break feed
+			}
+		}
+		close(taskChannel)
+		wg.Wait()
+		close(reportChannel)
+	}()
+
+	processed := 0
+	display := newProgress(opts, tasks)
+	var failed []taskReport
+	recorded := make(map[common.Season]bool)
+	done := make(map[string]bool, totalGames)
+
+	for result := range reportChannel {
+		if result.err != nil && ctx.Err() != nil && errors.Is(result.err, ctx.Err()) {
+			// Aborted by the interruption, the game is listed with the remaining ones
+			continue
+		}
+		if usage.OutOfCalls(result.err) {
+			// Not downloaded for lack of calls, the game is listed with the remaining ones
+			if outOfCallsErr == nil {
+				outOfCallsErr = result.err
+				close(stopped)
+			}
+			continue
+		}
+		processed++
+		done[result.id] = true
+		season := result.saved.season
+		report.add(opts.OutputDir, directory, result)
+		if result.err != nil {
+			failed = append(failed, result)
+		} else {
+			manifest, ok := manifests[season]
+			if !ok {
+				var err error
+				manifest, err = common.LoadManifest(opts.OutputDir, directory, season)
+				if err != nil {
+					fmt.Printf("Error loading manifest for season %v: %v\n", season, err)
+					manifest = nil
+				}
+				manifests[season] = manifest
+			}
+			if manifest != nil {
+				manifest.Record(result.saved.entry)
+				recorded[season] = true
+			}
+		}
+		display.done(result)
+	}
+	display.finish()
+
+	for season := range recorded {
+		if err := manifests[season].Save(); err != nil {
+			fmt.Printf("Error saving manifest for season %v: %v\n", season, err)
+		}
+	}
+
+	if len(failed) > 0 {
+		fmt.Printf("Errors:\n")
+		for _, result := range failed {
+			fmt.Printf("  %s: %v\n", result.id, result.err)
+		}
+	}
+
+	var remaining []task
+	for _, t := range tasks {
+		if !done[t.id] {
+			remaining = append(remaining, t)
+		}
+	}
+	report.finish(ctx.Err() != nil || outOfCallsErr != nil, remaining)
+	if path, err := report.write(opts); err != nil {
+		fmt.Printf("Error writing report: %v\n", err)
+	} else {
+		fmt.Printf("Report written to %s\n", path)
+	}
+
+	var stopErr error
+	switch {
+	case ctx.Err() != nil:
+		fmt.Printf("Interrupted: %d/%d games processed, %d remaining\n", processed, totalGames, len(remaining))
+		stopErr = fmt.Errorf("download interrupted: %w", ctx.Err())
+	case outOfCallsErr != nil:
+		fmt.Printf("Stopped, out of calls: %d/%d games processed, %d remaining\n", processed, totalGames, len(remaining))
+		stopErr = fmt.Errorf("download stopped: %w", outOfCallsErr)
+	default:
+		return nil
+	}
+
+	if len(remaining) == 0 {
+		return stopErr
+	}
+	remainingIDs := make([]string, 0, len(remaining))
+	for _, t := range remaining {
+		remainingIDs = append(remainingIDs, t.id)
+	}
+	path, err := writeRemaining(opts.OutputDir, directory, remainingIDs)
+	if err != nil {
+		return fmt.Errorf("%w, writing remaining games: %w", stopErr, err)
+	}
+	fmt.Printf("Remaining games written to %s, resume with --game-ids-file %s (or rerun with --incremental)\n", path, path)
+	return stopErr
+}
+
+// writeRemaining lists the games left by an interrupted or stopped download in the output directory,
+// in the format of --game-ids-file
+func writeRemaining(outputDir, directory string, gameIDs []string) (string, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(outputDir, fmt.Sprintf("remaining-%s.txt", directory))
+
+	content := fmt.Sprintf("# Games left by the download stopped at %s\n", time.Now().Format(time.RFC3339))
+	content += fmt.Sprintf("# Resume with: --game-ids-file %s\n", path)
+	for _, gameID := range gameIDs {
+		content += gameID + "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gamedl/internal/common"
+)
+
+const (
+	// redrawInterval is the time between two redraws of the progress bars on a terminal
+	redrawInterval = 200 * time.Millisecond
+	// summaryInterval is the time between two progress lines when the output isn't a terminal
+	summaryInterval = 10 * time.Second
+	barWidth        = 30
+)
+
+// progress displays the progress of a run as its games complete
+type progress interface {
+	done(result taskReport)
+	// finish displays the final state of the run, once every game is done
+	finish()
+}
+
+// newProgress returns the progress display of the run: a line per game when verbose,
+// a bar per season on a terminal, and periodic summary lines otherwise
+func newProgress(opts common.DownloadOptions, tasks []task) progress {
+	counts := newProgressCounts(tasks)
+	if opts.Verbose || len(tasks) == 0 {
+		return &lineProgress{counts: counts}
+	}
+	if isTerminal(os.Stdout) {
+		return startBarProgress(os.Stdout, counts)
+	}
+	return startSummaryProgress(os.Stdout, counts)
+}
+
+// isTerminal reports whether the file is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// seasonCounts are the counters of the games of a season
+type seasonCounts struct {
+	season    common.Season
+	total     int
+	processed int
+	errors    int
+}
+
+// progressCounts are the counters shared by the progress displays. They are safe for concurrent use.
+type progressCounts struct {
+	m         sync.Mutex
+	start     time.Time
+	total     int
+	processed int
+	errors    int
+	bytes     int64
+	// seasons are in the order their games are downloaded. Games whose season is only known
+	// from their payload are counted under the zero season until they're done.
+	seasons []*seasonCounts
+}
+
+func newProgressCounts(tasks []task) *progressCounts {
+	counts := &progressCounts{start: time.Now(), total: len(tasks)}
+	for _, t := range tasks {
+		counts.season(t.season).total++
+	}
+	return counts
+}
+
+// season returns the counters of a season, adding them if needed
+func (c *progressCounts) season(season common.Season) *seasonCounts {
+	for _, s := range c.seasons {
+		if s.season == season {
+			return s
+		}
+	}
+	s := &seasonCounts{season: season}
+	c.seasons = append(c.seasons, s)
+	return s
+}
+
+// done counts a game, under the season of its task
+func (c *progressCounts) done(result taskReport) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.processed++
+	c.bytes += result.saved.bytes
+	s := c.season(result.season)
+	s.processed++
+	if result.err != nil {
+		c.errors++
+		s.errors++
+	}
+}
+
+// rate returns the games downloaded per second since the start of the run
+func (c *progressCounts) rate() float64 {
+	elapsed := time.Since(c.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(c.processed) / elapsed
+}
+
+// eta returns the time left to download the given number of games at the current rate
+func (c *progressCounts) eta(left int) string {
+	if left == 0 {
+		return "done"
+	}
+	rate := c.rate()
+	if rate == 0 {
+		return "unknown"
+	}
+	return (time.Duration(float64(left) / rate * float64(time.Second))).Round(time.Second).String()
+}
+
+// summary describes the progress of the whole run on one line
+func (c *progressCounts) summary() string {
+	return fmt.Sprintf("Progress: %d/%d (%.2f%%) games | %.2f games/s | %s | ETA %s | %d errors",
+		c.processed, c.total, percent(c.processed, c.total), c.rate(), formatBytes(c.bytes), c.eta(c.total-c.processed), c.errors)
+}
+
+// lineProgress prints a line per game, the verbose display
+type lineProgress struct {
+	counts *progressCounts
+}
+
+func (p *lineProgress) done(result taskReport) {
+	p.counts.done(result)
+	status := "✅"
+	if result.err != nil {
+		fmt.Printf("Error: %v\n", result.err)
+		status = "❌"
+	}
+	fmt.Printf("[%v] %s Downloaded game %s | Progress: %d/%d (%.2f%%) games\n",
+		result.saved.season, status, result.id, p.counts.processed, p.counts.total, percent(p.counts.processed, p.counts.total))
+}
+
+func (p *lineProgress) finish() {}
+
+// barProgress redraws a progress bar per season on a terminal
+type barProgress struct {
+	out    io.Writer
+	counts *progressCounts
+	stop   chan struct{}
+	wg     sync.WaitGroup
+	// lines is the number of lines drawn by the previous redraw
+	lines int
+}
+
+func startBarProgress(out io.Writer, counts *progressCounts) *barProgress {
+	p := &barProgress{out: out, counts: counts, stop: make(chan struct{})}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(redrawInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.draw()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *barProgress) done(result taskReport) {
+	p.counts.done(result)
+}
+
+func (p *barProgress) finish() {
+	close(p.stop)
+	p.wg.Wait()
+	p.draw()
+}
+
+func (p *barProgress) draw() {
+	c := p.counts
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	var b strings.Builder
+	if p.lines > 0 {
+		// Back to the first line of the previous redraw
+		fmt.Fprintf(&b, "\x1b[%dA", p.lines)
+	}
+	for _, s := range c.seasons {
+		label := "by id"
+		if s.season != (common.Season{}) {
+			label = s.season.String()
+		}
+		filled := 0
+		if s.total > 0 {
+			filled = barWidth * s.processed / s.total
+		}
+		fmt.Fprintf(&b, "\r\x1b[K[%v] [%s%s] %d/%d | ETA %s | %d errors\n",
+			label, strings.Repeat("#", filled), strings.Repeat("-", barWidth-filled), s.processed, s.total, c.eta(s.total-s.processed), s.errors)
+	}
+	fmt.Fprintf(&b, "\r\x1b[K%s\n", c.summary())
+	p.lines = len(c.seasons) + 1
+
+	io.WriteString(p.out, b.String())
+}
+
+// summaryProgress prints a summary line at a regular interval, for logs
+type summaryProgress struct {
+	out    io.Writer
+	counts *progressCounts
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func startSummaryProgress(out io.Writer, counts *progressCounts) *summaryProgress {
+	p := &summaryProgress{out: out, counts: counts, stop: make(chan struct{})}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(summaryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.print()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *summaryProgress) done(result taskReport) {
+	p.counts.done(result)
+}
+
+func (p *summaryProgress) finish() {
+	close(p.stop)
+	p.wg.Wait()
+	p.print()
+}
+
+func (p *summaryProgress) print() {
+	p.counts.m.Lock()
+	defer p.counts.m.Unlock()
+	fmt.Fprintln(p.out, p.counts.summary())
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(part) / float64(total) * 100.0
+}
+
+// formatBytes returns a byte count in a human readable unit, e.g. 12.3 MB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
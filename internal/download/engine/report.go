@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gamedl/internal/common"
+)
+
+// Outcomes of a game in a run report
+const (
+	GameDownloaded = "downloaded"
+	GameFailed     = "failed"
+	// GameRemaining is a game an interrupted run didn't download
+	GameRemaining = "remaining"
+)
+
+// Report is the machine readable outcome of a download run
+type Report struct {
+	Provider    string        `json:"provider"`
+	Directory   string        `json:"directory"`
+	StartedAt   time.Time     `json:"started_at"`
+	FinishedAt  time.Time     `json:"finished_at"`
+	Interrupted bool          `json:"interrupted"`
+	Summary     ReportSummary `json:"summary"`
+	Games       []GameReport  `json:"games"`
+}
+
+// ReportSummary counts the games of a run by outcome
+type ReportSummary struct {
+	Total      int `json:"total"`
+	Downloaded int `json:"downloaded"`
+	Failed     int `json:"failed"`
+	Remaining  int `json:"remaining"`
+}
+
+// GameReport is the outcome of a game in a run
+type GameReport struct {
+	ID string `json:"id"`
+	// Year and Type are the season of the game, unset when it couldn't be found
+	Year   int    `json:"year,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// HTTPStatus is the status code of the last play by play response, 0 when none was received
+	HTTPStatus int `json:"http_status,omitempty"`
+	// Attempts is the number of attempts of the play by play request, retries included
+	Attempts int `json:"attempts,omitempty"`
+	// Bytes is the size of the payload received from the provider
+	Bytes      int64 `json:"bytes,omitempty"`
+	DurationMs int64 `json:"duration_ms,omitempty"`
+	// Path is the game file, set once the game is saved
+	Path string `json:"path,omitempty"`
+}
+
+func newReport(source Source, total int) *Report {
+	return &Report{
+		Provider:  source.Provider(),
+		Directory: source.Directory(),
+		StartedAt: time.Now().UTC(),
+		Summary:   ReportSummary{Total: total},
+		Games:     []GameReport{},
+	}
+}
+
+func (r *Report) add(outputDir, directory string, result taskReport) {
+	game := GameReport{
+		ID:         result.id,
+		Year:       result.saved.season.Year,
+		Type:       result.saved.season.Type,
+		Status:     GameDownloaded,
+		HTTPStatus: result.stats.StatusCode,
+		Attempts:   result.stats.Attempts,
+		Bytes:      result.saved.bytes,
+		DurationMs: result.duration.Milliseconds(),
+	}
+	if result.err != nil {
+		game.Status = GameFailed
+		game.Error = result.err.Error()
+		r.Summary.Failed++
+	} else {
+		seasonDir := common.GetSeasonDirectoryPath(outputDir, directory, game.Year, game.Type)
+		game.Path = filepath.Join(seasonDir, result.saved.entry.Name)
+		r.Summary.Downloaded++
+	}
+	r.Games = append(r.Games, game)
+}
+
+// finish closes the report, listing the tasks an interrupted run didn't complete
+func (r *Report) finish(interrupted bool, remaining []task) {
+	r.FinishedAt = time.Now().UTC()
+	r.Interrupted = interrupted
+	for _, t := range remaining {
+		r.Games = append(r.Games, GameReport{ID: t.id, Year: t.season.Year, Type: t.season.Type, Status: GameRemaining})
+	}
+	r.Summary.Remaining = len(remaining)
+}
+
+// write saves the report to opts.ReportFile, or to a file of the reports directory named after the run
+func (r *Report) write(opts common.DownloadOptions) (string, error) {
+	path := opts.ReportFile
+	if path == "" {
+		name := fmt.Sprintf("%s-%s.json", r.Directory, r.StartedAt.Format("20060102T150405Z"))
+		path = filepath.Join(opts.OutputDir, common.ReportsDirectory, name)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("creating reports directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling report: %w", err)
+	}
+	if err := common.WriteFileAtomic(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing report %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// ReadReport reads the report of a previous run
+func ReadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading report: %w", err)
+	}
+	report := &Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("unmarshaling report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// FailedGames returns the games the run failed to download
+func (r *Report) FailedGames() []GameReport {
+	var failed []GameReport
+	for _, game := range r.Games {
+		if game.Status == GameFailed {
+			failed = append(failed, game)
+		}
+	}
+	return failed
+}
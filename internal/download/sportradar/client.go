@@ -3,36 +3,103 @@ package sportradar
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
+	"gamedl/internal/common"
 	"gamedl/lib/web/clients/sportsradar"
 )
 
-func createSportRadarClientWithNCAB() (*sportsradar.Client, error) {
-	apiKey := os.Getenv("SPORTRADAR_NCAAB_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("SPORTRADAR_NCAAB_KEY environment variable not set")
-	}
+func createSportRadarClientWithNCAB(opts common.DownloadOptions) (*sportsradar.Client, error) {
+	return createSportRadarClient(opts, "ncaab", "SPORTRADAR_NCAAB_KEY", sportsradar.WithNcaabAPIKeys, sportsradar.WithNcaabAPIConfig)
+}
 
-	client := sportsradar.NewClient(sportsradar.WithNcaabAPIKey(apiKey))
-	return client, nil
+func createSportRadarClientWithNCAF(opts common.DownloadOptions) (*sportsradar.Client, error) {
+	return createSportRadarClient(opts, "ncaaf", "SPORTRADAR_NCAAF_KEY", sportsradar.WithNcaafAPIKeys, sportsradar.WithNcaafAPIConfig)
+}
+
+func createSportRadarClientWithNfl(opts common.DownloadOptions) (*sportsradar.Client, error) {
+	return createSportRadarClient(opts, "nfl", "SPORTRADAR_NFL_KEY", sportsradar.WithNflAPIKeys, sportsradar.WithNflAPIConfig)
+}
+
+func createSportRadarClientWithNba(opts common.DownloadOptions) (*sportsradar.Client, error) {
+	return createSportRadarClient(opts, "nba", "SPORTRADAR_NBA_KEY", sportsradar.WithNbaAPIKeys, sportsradar.WithNbaAPIConfig)
 }
 
-func createSportRadarClientWithNCAF() (*sportsradar.Client, error) {
-	apiKey := os.Getenv("SPORTRADAR_NCAAF_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("SPORTRADAR_NCAAF_KEY environment variable not set")
+// createSportRadarClient creates a client for a competition with the api keys of the environment
+// variable, comma-separated, followed by the ones of opts.SportRadarKeys, the API of opts.SportRadarAPIs
+// and the transport settings of opts.HTTP
+func createSportRadarClient(opts common.DownloadOptions, competition, env string,
+	withKeys func(...string) sportsradar.ClientOption, withAPIConfig func(sportsradar.APIConfig) sportsradar.ClientOption) (*sportsradar.Client, error) {
+	var apiKeys []string
+	for _, key := range strings.Split(os.Getenv(env), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			apiKeys = append(apiKeys, key)
+		}
+	}
+	apiKeys = append(apiKeys, opts.SportRadarKeys[competition]...)
+	if len(apiKeys) == 0 && !opts.HTTP.Replay {
+		return nil, fmt.Errorf("%s environment variable not set and no sportradar.keys.%s configured", env, competition)
+	}
+
+	api := opts.SportRadarAPIs[competition]
+	apiConfig := sportsradar.APIConfig{AccessLevel: sportsradar.AccessLevel(api.AccessLevel), Version: api.Version, Locale: api.Locale}
+	if err := sportsradar.ValidateAPIConfig(competition, apiConfig); err != nil {
+		return nil, err
+	}
+
+	proxy, err := opts.HTTP.ProxyURL()
+	if err != nil {
+		return nil, err
 	}
 
-	client := sportsradar.NewClient(sportsradar.WithNcaafAPIKey(apiKey))
+	options := []sportsradar.ClientOption{
+		withKeys(apiKeys...),
+		withAPIConfig(apiConfig),
+		sportsradar.WithRetryPolicy(opts.Retry),
+		sportsradar.WithRateLimits(opts.RateLimits["sportradar"]),
+	}
+	if opts.KeyRotation != "" {
+		options = append(options, sportsradar.WithKeyRotation(sportsradar.Rotation(opts.KeyRotation)))
+	}
+	if opts.Usage != nil {
+		options = append(options, sportsradar.WithUsage(opts.Usage))
+	}
+	if opts.HTTP.Transport != nil {
+		options = append(options, sportsradar.WithTransport(opts.HTTP.Transport))
+	}
+	if opts.HTTP.Timeout > 0 {
+		options = append(options, sportsradar.WithTimeout(opts.HTTP.Timeout))
+	}
+	if proxy != nil {
+		options = append(options, sportsradar.WithProxy(proxy))
+	}
+	if opts.HTTP.UserAgent != "" {
+		options = append(options, sportsradar.WithUserAgent(opts.HTTP.UserAgent))
+	}
+	if opts.SportRadarBaseURL != "" {
+		options = append(options, sportsradar.WithBaseURL(opts.SportRadarBaseURL))
+	}
+
+	client := sportsradar.NewClient(options...)
 	return client, nil
 }
 
-func createSportRadarClientWithNba() (*sportsradar.Client, error) {
-	apiKey := os.Getenv("SPORTRADAR_NBA_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("SPORTRADAR_NBA_KEY environment variable not set")
+// printKeyStats prints the requests sent with each api key of the client, when it has several
+func printKeyStats(client *sportsradar.Client) {
+	stats := client.KeyStats()
+	if len(stats) < 2 {
+		return
 	}
 
-	client := sportsradar.NewClient(sportsradar.WithNbaAPIKey(apiKey))
-	return client, nil
+	fmt.Println("API keys:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, key := range stats {
+		status := "ok"
+		if key.Exhausted {
+			status = "exhausted (" + key.Reason + ")"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%d requests\t%s\n", key.Competition, key.KeyID, key.Requests, status)
+	}
+	w.Flush()
 }
@@ -1,174 +1,77 @@
 package sportradar
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
-	"sync"
+	"io"
 
 	"gamedl/internal/common"
+	"gamedl/internal/download/engine"
 	sportsradar2 "gamedl/lib/web/clients/sportsradar"
 )
 
-func gamesPerYearNcaaf(client *sportsradar2.Client, seasons *sportsradar2.NcaafSeasonsInfo) (map[int][]*sportsradar2.NcaafGame, error) {
-	years := seasons.Years()
-	yearToGames := make(map[int][]*sportsradar2.NcaafGame)
-
-	for _, year := range years {
-		schedule, err := client.GetNcaafSeasonSchedule(year)
-		if err != nil {
-			return nil, fmt.Errorf("getting game schedule for year %v: %w", year, err)
-		}
-
-		yearToGames[year] = make([]*sportsradar2.NcaafGame, 0, 1024)
-		for _, week := range schedule.Weeks {
-			for _, game := range week.Games {
-				yearToGames[year] = append(yearToGames[year], game)
-			}
-		}
+// ncaafDirectory is the directory SportRadar NCAAF games are saved into
+const ncaafDirectory = "ncaaf"
+
+// ncaafSource lists and fetches SportRadar NCAAF games for the download engine
+func ncaafSource(client *sportsradar2.Client) *scheduleSource {
+	return &scheduleSource{
+		client:      client,
+		directory:   ncaafDirectory,
+		getSeasons:  ncaafSeasons,
+		getSchedule: ncaafSchedule,
+		openPbp:     client.OpenNcaafPbpOfGame,
+		pbpURL:      client.NcaafPbpOfGameURL,
+		readPbp:     readNcaafPbp,
 	}
-
-	return yearToGames, nil
 }
 
-func fetchAndSaveGameNcaaf(client *sportsradar2.Client, gameID string, year int, outputDir string) error {
-	gamePbpData, err := client.GetNcaafPbpOfGameRaw(gameID)
+func ncaafSeasons(ctx context.Context, client *sportsradar2.Client) ([]common.Season, error) {
+	seasonsInfo, err := client.GetNcaafSeasons(ctx)
 	if err != nil {
-		return fmt.Errorf("fetching game pbp: %w", err)
+		return nil, err
 	}
 
-	pathtoFile := common.GetGameFilePath(outputDir, "ncaaf", year, gameID)
-
-	bytesBuffer := bytes.NewBuffer([]byte{})
-	err = json.Indent(bytesBuffer, gamePbpData, "", "  ")
-	if err != nil {
-		return fmt.Errorf("indenting game pbp: %w", err)
-	}
-
-	defer func() {
-		bytesBuffer.Reset()
-		bytesBuffer = nil
-	}()
-
-	err = os.WriteFile(pathtoFile, bytesBuffer.Bytes(), 0o644)
-	if err != nil {
-		return fmt.Errorf("saving game pbp: %w", err)
+	seasons := make([]common.Season, 0, len(seasonsInfo.Seasons))
+	for _, seasonInfo := range seasonsInfo.Seasons {
+		seasons = append(seasons, common.Season{Year: seasonInfo.Year, Type: seasonInfo.Type.Code})
 	}
-
-	return nil
+	return seasons, nil
 }
 
-func DownloadNCAAF(seasons []int, concurrency int, outputDir string) error {
-	client, err := createSportRadarClientWithNCAF()
-	if err != nil {
-		return fmt.Errorf("failed to create SportRadar client: %w", err)
-	}
-
-	fmt.Println("Getting seasons data...")
-	// Fetch seasons
-	seasonsInfo, err := client.GetNcaafSeasons()
-	if err != nil {
-		return fmt.Errorf("getting seasons: %w", err)
-	}
-
-	if len(seasons) > 0 {
-		seasonsInfo.FilterYears(seasons)
-	}
-
-	fmt.Printf("Getting game ids for seasons %v...\n", seasonsInfo.Years())
-
-	// Get games per year
-	yearToGames, err := gamesPerYearNcaaf(client, seasonsInfo)
+func ncaafSchedule(ctx context.Context, client *sportsradar2.Client, season common.Season) ([]scheduledGame, error) {
+	schedule, err := client.GetNcaafSeasonSchedule(ctx, season.Year, season.Type)
 	if err != nil {
-		return fmt.Errorf("getting games: %w", err)
+		return nil, err
 	}
 
-	totalGames := 0
-	for year, games := range yearToGames {
-		gameStatus := make(map[string]int)
-		for _, game := range games {
-			gameStatus[game.Status]++
-		}
-		totalGames += gameStatus["closed"]
-		fmt.Printf("Year: %v, Games: %v\n", year, len(games))
-		for status, count := range gameStatus {
-			fmt.Printf("  Status: %v, Count: %v\n", status, count)
+	var games []scheduledGame
+	for _, week := range schedule.Weeks {
+		for _, game := range week.Games {
+			games = append(games, scheduledGame{ID: game.ID, Status: game.Status, Scheduled: game.Scheduled, Teams: game.TeamIdentifiers()})
 		}
 	}
+	return games, nil
+}
 
-	if totalGames == 0 {
-		fmt.Println("No closed games found")
-		return nil
+func readNcaafPbp(payload io.Reader) (common.Season, scheduledGame, error) {
+	pbp, err := decodePbp[sportsradar2.NcaafGamePbp](payload)
+	if err != nil {
+		return common.Season{}, scheduledGame{}, err
 	}
-
-	tokenChannel := make(chan struct{}, concurrency)
-	for i := 0; i < concurrency; i++ {
-		tokenChannel <- struct{}{}
+	if pbp.Summary == nil || pbp.Summary.Season == nil {
+		return common.Season{}, scheduledGame{}, fmt.Errorf("game pbp has no season")
 	}
 
-	wg := sync.WaitGroup{}
-	reportChannel := make(chan GameProcessReport, totalGames/concurrency+1)
-
-	for year, games := range yearToGames {
-		err := common.CreateYearDirectory(outputDir, "ncaaf", year)
-		if err != nil {
-			return fmt.Errorf("creating directory for year %d: %w", year, err)
-		}
-
-		for _, game := range games {
-			if game.Status != "closed" {
-				continue
-			}
-			wg.Add(1)
-			go func(gameID string, gameYear int) {
-				<-tokenChannel
-				defer func() {
-					tokenChannel <- struct{}{}
-					wg.Done()
-				}()
-
-				report := GameProcessReport{
-					Id:   gameID,
-					Year: gameYear,
-				}
-
-				fetchAndSaveError := fetchAndSaveGameNcaaf(client, gameID, gameYear, outputDir)
-				if fetchAndSaveError != nil {
-					report.Err = fetchAndSaveError
-				}
-				reportChannel <- report
-			}(game.ID, year)
-		}
-	}
-
-	go func() {
-		wg.Wait()
-		close(reportChannel)
-	}()
-
-	processed := 0
-	var reportErrors []GameProcessReport
-
-	for report := range reportChannel {
-		processed++
-		status := "✅"
-		if report.Err != nil {
-			reportErrors = append(reportErrors, report)
-			fmt.Printf("Error: %v\n", report.Err)
-			status = "❌"
-		}
-
-		fmt.Printf("[%d] %s Downloaded game %s | Progress: %d/%d (%.2f%%) games\n",
-			report.Year, status, report.Id, processed, totalGames, (float64(processed)/float64(totalGames))*100.0)
-	}
+	return common.Season{Year: pbp.Summary.Season.Year, Type: pbp.Summary.Season.Type}, scheduledGame{Status: pbp.Status, Scheduled: pbp.Scheduled}, nil
+}
 
-	if len(reportErrors) > 0 {
-		fmt.Printf("Errors:\n")
-		for _, report := range reportErrors {
-			fmt.Printf("  %s: %v\n", report.Id, report.Err)
-		}
+func DownloadNCAAF(ctx context.Context, opts common.DownloadOptions) error {
+	client, err := createSportRadarClientWithNCAF(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create SportRadar client: %w", err)
 	}
 
-	return nil
+	defer printKeyStats(client)
+	return engine.Download(ctx, ncaafSource(client), opts)
 }
@@ -1,8 +1,78 @@
 package sportradar
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"io"
 
-func DownloadNFL(seasons []int, concurrency int, outputDir string) error {
-	fmt.Println("NFL download for SportRadar not yet implemented")
-	return fmt.Errorf("NFL download for SportRadar not yet implemented")
+	"gamedl/internal/common"
+	"gamedl/internal/download/engine"
+	sportsradar2 "gamedl/lib/web/clients/sportsradar"
+)
+
+// nflDirectory is the directory SportRadar NFL games are saved into,
+// apart from the BetGenius NFL games so both providers can be compared
+const nflDirectory = "nfl-sportradar"
+
+// nflSource lists and fetches SportRadar NFL games for the download engine
+func nflSource(client *sportsradar2.Client) *scheduleSource {
+	return &scheduleSource{
+		client:      client,
+		directory:   nflDirectory,
+		getSeasons:  nflSeasons,
+		getSchedule: nflSchedule,
+		openPbp:     client.OpenNflPbpOfGame,
+		pbpURL:      client.NflPbpOfGameURL,
+		readPbp:     readNflPbp,
+	}
+}
+
+func nflSeasons(ctx context.Context, client *sportsradar2.Client) ([]common.Season, error) {
+	seasonsInfo, err := client.GetNflSeasons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seasons := make([]common.Season, 0, len(seasonsInfo.Seasons))
+	for _, seasonInfo := range seasonsInfo.Seasons {
+		seasons = append(seasons, common.Season{Year: seasonInfo.Year, Type: seasonInfo.Type.Code})
+	}
+	return seasons, nil
+}
+
+func nflSchedule(ctx context.Context, client *sportsradar2.Client, season common.Season) ([]scheduledGame, error) {
+	schedule, err := client.GetNflSeasonSchedule(ctx, season.Year, season.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var games []scheduledGame
+	for _, week := range schedule.Weeks {
+		for _, game := range week.Games {
+			games = append(games, scheduledGame{ID: game.ID, Status: game.Status, Scheduled: game.Scheduled, Teams: game.TeamIdentifiers()})
+		}
+	}
+	return games, nil
+}
+
+func readNflPbp(payload io.Reader) (common.Season, scheduledGame, error) {
+	pbp, err := decodePbp[sportsradar2.NflGamePbp](payload)
+	if err != nil {
+		return common.Season{}, scheduledGame{}, err
+	}
+	if pbp.Summary == nil || pbp.Summary.Season == nil {
+		return common.Season{}, scheduledGame{}, fmt.Errorf("game pbp has no season")
+	}
+
+	return common.Season{Year: pbp.Summary.Season.Year, Type: pbp.Summary.Season.Type}, scheduledGame{Status: pbp.Status, Scheduled: pbp.Scheduled}, nil
+}
+
+func DownloadNFL(ctx context.Context, opts common.DownloadOptions) error {
+	client, err := createSportRadarClientWithNfl(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create SportRadar client: %w", err)
+	}
+
+	defer printKeyStats(client)
+	return engine.Download(ctx, nflSource(client), opts)
 }
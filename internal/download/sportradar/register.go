@@ -0,0 +1,15 @@
+package sportradar
+
+import "gamedl/internal/registry"
+
+func init() {
+	registry.RegisterProvider(registry.Provider{
+		Name:        "sportradar",
+		DisplayName: "SportRadar",
+		Aliases:     []string{"sr"},
+	})
+	registry.RegisterCompetition("sportradar", registry.Competition{Name: "nba", Directory: nbaDirectory, Download: DownloadNBA, Watch: WatchNBA})
+	registry.RegisterCompetition("sportradar", registry.Competition{Name: "ncaab", Directory: ncaabDirectory, Download: DownloadNCAAB})
+	registry.RegisterCompetition("sportradar", registry.Competition{Name: "ncaaf", Directory: ncaafDirectory, Download: DownloadNCAAF})
+	registry.RegisterCompetition("sportradar", registry.Competition{Name: "nfl", Directory: nflDirectory, Download: DownloadNFL, Watch: WatchNFL})
+}
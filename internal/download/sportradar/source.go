@@ -0,0 +1,125 @@
+package sportradar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"gamedl/internal/common"
+	"gamedl/internal/download/engine"
+	sportsradar2 "gamedl/lib/web/clients/sportsradar"
+)
+
+// scheduleSource lists and fetches the games of a SportRadar competition for the download engine.
+// The seasons, schedule and play by play APIs work the same for every competition, only their
+// models differ, so each competition gives the functions reading them.
+type scheduleSource struct {
+	statusPolicy
+	client    *sportsradar2.Client
+	directory string
+	// getSeasons returns every season of the competition
+	getSeasons func(ctx context.Context, client *sportsradar2.Client) ([]common.Season, error)
+	// getSchedule returns the games of the schedule of a season
+	getSchedule func(ctx context.Context, client *sportsradar2.Client, season common.Season) ([]scheduledGame, error)
+	openPbp     func(ctx context.Context, gameID string) (io.ReadCloser, error)
+	pbpURL      func(gameID string) string
+	// readPbp reads the season, status and scheduled time of a game from its play by play
+	readPbp func(payload io.Reader) (common.Season, scheduledGame, error)
+}
+
+// scheduledGame is a game as listed in the schedule of a season
+type scheduledGame struct {
+	ID        string
+	Status    string
+	Scheduled time.Time
+	Teams     []string
+}
+
+// updatedMarker returns the change marker of a game recorded in the manifests. SportRadar
+// schedules carry no update stamp, so the scheduled time of the game is used.
+func updatedMarker(scheduled time.Time) string {
+	return scheduled.UTC().Format(time.RFC3339)
+}
+
+func (s *scheduleSource) Provider() string {
+	return "sportradar"
+}
+
+func (s *scheduleSource) Directory() string {
+	return s.directory
+}
+
+func (s *scheduleSource) Seasons(ctx context.Context, opts common.DownloadOptions) ([]common.Season, error) {
+	allSeasons, err := s.getSeasons(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	seasons := make([]common.Season, 0, len(allSeasons))
+	for _, season := range allSeasons {
+		if len(opts.Seasons) > 0 && !slices.Contains(opts.Seasons, season.Year) {
+			continue
+		}
+		if !slices.Contains(opts.SeasonTypes, season.Type) {
+			continue
+		}
+		seasons = append(seasons, season)
+	}
+	return seasons, nil
+}
+
+func (s *scheduleSource) Games(ctx context.Context, season common.Season) ([]engine.Game, error) {
+	schedule, err := s.getSchedule(ctx, s.client, season)
+	if err != nil {
+		return nil, err
+	}
+
+	games := make([]engine.Game, 0, len(schedule))
+	for _, game := range schedule {
+		games = append(games, engine.Game{
+			ID:        game.ID,
+			Season:    season,
+			Status:    game.Status,
+			Updated:   updatedMarker(game.Scheduled),
+			Scheduled: game.Scheduled,
+			Teams:     game.Teams,
+		})
+	}
+	return games, nil
+}
+
+func (s *scheduleSource) OpenPbp(ctx context.Context, gameID string) (io.ReadCloser, error) {
+	return s.openPbp(ctx, gameID)
+}
+
+func (s *scheduleSource) PbpURL(gameID string) string {
+	return s.pbpURL(gameID)
+}
+
+// LocateGame reads the season of a game requested by id from its payload
+func (s *scheduleSource) LocateGame(gameID string, payload io.Reader) (engine.Game, error) {
+	season, game, err := s.readPbp(payload)
+	if err != nil {
+		return engine.Game{}, err
+	}
+
+	return engine.Game{
+		ID:        gameID,
+		Season:    season,
+		Status:    game.Status,
+		Updated:   updatedMarker(game.Scheduled),
+		Scheduled: game.Scheduled,
+	}, nil
+}
+
+// decodePbp unmarshals the play by play of a game
+func decodePbp[P any](payload io.Reader) (*P, error) {
+	pbp := new(P)
+	if err := json.NewDecoder(payload).Decode(pbp); err != nil {
+		return nil, fmt.Errorf("unmarshaling game pbp: %w", err)
+	}
+	return pbp, nil
+}
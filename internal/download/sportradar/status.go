@@ -0,0 +1,36 @@
+package sportradar
+
+import (
+	"slices"
+
+	"gamedl/internal/download/engine"
+)
+
+// defaultStatuses are the game statuses downloaded when none are requested.
+// Closed games are over and their stats have been verified.
+var defaultStatuses = []string{"closed"}
+
+// finalStatuses are the game statuses after which a game payload doesn't change anymore
+var finalStatuses = []string{"closed"}
+
+func isFinal(status string) bool {
+	return slices.Contains(finalStatuses, status)
+}
+
+// liveStatuses are the game statuses whose payload still changes. Complete games are over
+// but their stats are still being verified until the game gets closed.
+var liveStatuses = []string{"inprogress", "halftime", "complete"}
+
+// upcomingStatuses are the statuses of the games that haven't started yet, delayed games included
+var upcomingStatuses = []string{"created", "scheduled", "delayed"}
+
+// statusPolicy gives the download engine the SportRadar game statuses
+type statusPolicy struct{}
+
+func (statusPolicy) DefaultStatuses() []string {
+	return defaultStatuses
+}
+
+func (statusPolicy) IsFinal(game engine.Game) bool {
+	return isFinal(game.Status)
+}
@@ -0,0 +1,143 @@
+package sportradar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"gamedl/internal/common"
+	"gamedl/internal/watch"
+)
+
+// scheduleWatchSource finds the live games of a competition in the schedules of the watched seasons
+type scheduleWatchSource struct {
+	source  *scheduleSource
+	seasons []common.Season
+	filter  common.GameFilter
+}
+
+func (s *scheduleWatchSource) LiveGames(ctx context.Context) ([]watch.Game, time.Time, error) {
+	var live []watch.Game
+	var next time.Time
+	for _, season := range s.seasons {
+		schedule, err := s.source.getSchedule(ctx, s.source.client, season)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("getting game schedule for season %v: %w", season, err)
+		}
+
+		for _, game := range schedule {
+			if !s.filter.Match(game.Scheduled, game.Teams...) {
+				continue
+			}
+			switch {
+			case slices.Contains(liveStatuses, game.Status):
+				live = append(live, watch.Game{
+					ID:      game.ID,
+					Season:  season,
+					Updated: updatedMarker(game.Scheduled),
+				})
+			case slices.Contains(upcomingStatuses, game.Status):
+				if next.IsZero() || game.Scheduled.Before(next) {
+					next = game.Scheduled
+				}
+			}
+		}
+	}
+	return live, next, nil
+}
+
+func (s *scheduleWatchSource) FetchPbp(ctx context.Context, game watch.Game) (watch.Payload, error) {
+	body, err := s.source.openPbp(ctx, game.ID)
+	if err != nil {
+		return watch.Payload{}, err
+	}
+	defer body.Close()
+
+	gamePbpData, err := io.ReadAll(body)
+	if err != nil {
+		return watch.Payload{}, fmt.Errorf("reading game pbp: %w", err)
+	}
+	_, pbp, err := s.source.readPbp(bytes.NewReader(gamePbpData))
+	if err != nil {
+		return watch.Payload{}, fmt.Errorf("reading game pbp: %w", err)
+	}
+
+	return watch.Payload{
+		Data:      gamePbpData,
+		SourceURL: s.source.pbpURL(game.ID),
+		Status:    pbp.Status,
+		Final:     isFinal(pbp.Status),
+		Live:      slices.Contains(liveStatuses, pbp.Status),
+	}, nil
+}
+
+// WatchNBA polls the games in progress of the selected seasons until they are closed, see watchSchedule
+func WatchNBA(ctx context.Context, opts common.DownloadOptions, interval time.Duration) error {
+	client, err := createSportRadarClientWithNba(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create SportRadar client: %w", err)
+	}
+	defer printKeyStats(client)
+
+	return watchSchedule(ctx, nbaSource(client), opts, interval)
+}
+
+// WatchNFL polls the games in progress of the selected seasons until they are closed, see watchSchedule
+func WatchNFL(ctx context.Context, opts common.DownloadOptions, interval time.Duration) error {
+	client, err := createSportRadarClientWithNfl(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create SportRadar client: %w", err)
+	}
+	defer printKeyStats(client)
+
+	return watchSchedule(ctx, nflSource(client), opts, interval)
+}
+
+// watchSchedule polls the games in progress of the selected seasons of a competition until the
+// last game of the filter's window is closed. Without selected seasons the two latest ones are
+// watched, since a season can span two years.
+func watchSchedule(ctx context.Context, source *scheduleSource, opts common.DownloadOptions, interval time.Duration) error {
+	allSeasons, err := source.getSeasons(ctx, source.client)
+	if err != nil {
+		return fmt.Errorf("getting seasons: %w", err)
+	}
+	var years []int
+	for _, season := range allSeasons {
+		if slices.Contains(opts.SeasonTypes, season.Type) {
+			years = append(years, season.Year)
+		}
+	}
+	if len(opts.Seasons) > 0 {
+		years = opts.Seasons
+	} else {
+		years = latestYears(years, 2)
+	}
+
+	var seasons []common.Season
+	for _, season := range allSeasons {
+		if slices.Contains(opts.SeasonTypes, season.Type) && slices.Contains(years, season.Year) {
+			seasons = append(seasons, season)
+		}
+	}
+	if len(seasons) == 0 {
+		return fmt.Errorf("no seasons to watch")
+	}
+	fmt.Printf("Watching seasons %v every %v...\n", seasons, interval)
+
+	watchSource := &scheduleWatchSource{source: source, seasons: seasons, filter: opts.Filter}
+	return watch.Poll(ctx, watch.Options{Competition: source.directory, Interval: interval, OutputDir: opts.OutputDir, Storage: opts.Storage}, watchSource)
+}
+
+// latestYears returns the n most recent distinct years
+func latestYears(years []int, n int) []int {
+	sorted := slices.Clone(years)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	if len(sorted) > n {
+		sorted = sorted[len(sorted)-n:]
+	}
+	return sorted
+}
@@ -0,0 +1,24 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type WatchConfig struct {
+	Config
+	// Interval is the time between two polls of the schedule and of the play by play of the games in progress
+	Interval time.Duration
+}
+
+func Watch(ctx context.Context, config WatchConfig) error {
+	provider, competition, err := lookup(config.Config)
+	if err != nil {
+		return err
+	}
+	if competition.Watch == nil {
+		return fmt.Errorf("unsupported competition for watching %s: %s", provider.DisplayName, config.Competition)
+	}
+	return competition.Watch(ctx, config.DownloadOptions, config.Interval)
+}
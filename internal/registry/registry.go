@@ -0,0 +1,246 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gamedl/internal/common"
+)
+
+// Competition is a competition a provider can download games of
+type Competition struct {
+	Name string
+	// Directory is the directory the games are saved into under the output directory
+	Directory string
+	Download  func(ctx context.Context, opts common.DownloadOptions) error
+	// Watch follows the games in progress, nil when the competition can't be watched
+	Watch func(ctx context.Context, opts common.DownloadOptions, interval time.Duration) error
+}
+
+// Provider is a data provider and the competitions it supports
+type Provider struct {
+	Name        string
+	DisplayName string
+	Aliases     []string
+	// SeasonTypes are the season types the provider splits its seasons by, every season type when empty
+	SeasonTypes  []string
+	competitions []Competition
+}
+
+// Competitions returns the competitions of the provider, sorted by name
+func (p *Provider) Competitions() []Competition {
+	competitions := slices.Clone(p.competitions)
+	sort.Slice(competitions, func(i, j int) bool { return competitions[i].Name < competitions[j].Name })
+	return competitions
+}
+
+// Competition returns the competition of the given name
+func (p *Provider) Competition(name string) (Competition, error) {
+	for _, competition := range p.competitions {
+		if competition.Name == name {
+			return competition, nil
+		}
+	}
+	names := make([]string, 0, len(p.competitions))
+	for _, competition := range p.Competitions() {
+		names = append(names, competition.Name)
+	}
+	return Competition{}, fmt.Errorf("unsupported competition %s for %s. Valid options: %s", name, p.DisplayName, strings.Join(names, ", "))
+}
+
+// CompetitionByDirectory returns the competition whose games are saved into the given directory
+func (p *Provider) CompetitionByDirectory(directory string) (Competition, error) {
+	for _, competition := range p.competitions {
+		if competition.Directory == directory {
+			return competition, nil
+		}
+	}
+	return Competition{}, fmt.Errorf("no %s competition saves its games into %s", p.DisplayName, directory)
+}
+
+// Analysis is an analysis of the downloaded games of a competition
+type Analysis struct {
+	Competition string
+	Name        string
+	Description string
+	// Provider is the provider whose downloads of the competition the analysis reads
+	Provider string
+	// Directory overrides the competition directory the games are read from, see GamesDirectory
+	Directory string
+	// RequiresYears is false for analyses that search the games recursively instead of by season
+	RequiresYears bool
+	Run           func(opts AnalysisOptions) error
+}
+
+// AnalysisOptions are the options an analysis runs with
+type AnalysisOptions struct {
+	InputDir  string
+	OutputDir string
+	Directory string
+	Seasons   []common.Season
+}
+
+// GamesDirectory returns the directory the analysis reads its games from: its Directory when set, else
+// the directory the provider saves the games of the competition into, else the competition name
+func (a Analysis) GamesDirectory() string {
+	if a.Directory != "" {
+		return a.Directory
+	}
+	if a.Provider != "" {
+		if _, competition, err := LookupCompetition(a.Provider, a.Competition); err == nil {
+			return competition.Directory
+		}
+	}
+	return a.Competition
+}
+
+var (
+	providers []*Provider
+	analyses  []Analysis
+)
+
+// RegisterProvider adds a provider. Its competitions are added with RegisterCompetition.
+func RegisterProvider(provider Provider) {
+	providers = append(providers, &provider)
+}
+
+// RegisterCompetition adds a competition to a registered provider
+func RegisterCompetition(provider string, competition Competition) {
+	p, err := LookupProvider(provider)
+	if err != nil {
+		panic(fmt.Sprintf("registering competition %s: %v", competition.Name, err))
+	}
+	p.competitions = append(p.competitions, competition)
+}
+
+// RegisterAnalysis adds an analysis of a competition
+func RegisterAnalysis(analysis Analysis) {
+	analyses = append(analyses, analysis)
+}
+
+// Providers returns the registered providers, sorted by name
+func Providers() []*Provider {
+	sorted := slices.Clone(providers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+// LookupProvider returns the provider of the given name or alias
+func LookupProvider(nameOrAlias string) (*Provider, error) {
+	for _, provider := range providers {
+		if provider.Name == nameOrAlias || slices.Contains(provider.Aliases, nameOrAlias) {
+			return provider, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid provider %s. Valid options: %s", nameOrAlias, strings.Join(ProviderNames(), ", "))
+}
+
+// LookupCompetition returns the competition of a provider given by name or alias
+func LookupCompetition(provider, competition string) (*Provider, Competition, error) {
+	p, err := LookupProvider(provider)
+	if err != nil {
+		return nil, Competition{}, err
+	}
+	c, err := p.Competition(competition)
+	if err != nil {
+		return nil, Competition{}, err
+	}
+	return p, c, nil
+}
+
+// ProviderNames returns the names and aliases of every provider
+func ProviderNames() []string {
+	var names []string
+	for _, provider := range Providers() {
+		names = append(names, provider.Name)
+		names = append(names, provider.Aliases...)
+	}
+	return names
+}
+
+// CompetitionNames returns the names of the competitions supported by any provider, sorted
+func CompetitionNames() []string {
+	var names []string
+	for _, provider := range providers {
+		for _, competition := range provider.competitions {
+			if !slices.Contains(names, competition.Name) {
+				names = append(names, competition.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WatchableCompetitionNames returns the names of the competitions that can be watched with any provider, sorted
+func WatchableCompetitionNames() []string {
+	var names []string
+	for _, provider := range providers {
+		for _, competition := range provider.competitions {
+			if competition.Watch != nil && !slices.Contains(names, competition.Name) {
+				names = append(names, competition.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Analyses returns the registered analyses, sorted by competition and name
+func Analyses() []Analysis {
+	sorted := slices.Clone(analyses)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Competition != sorted[j].Competition {
+			return sorted[i].Competition < sorted[j].Competition
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// AnalysisCompetitionNames returns the names of the competitions with at least one analysis, sorted
+func AnalysisCompetitionNames() []string {
+	var names []string
+	for _, analysis := range analyses {
+		if !slices.Contains(names, analysis.Competition) {
+			names = append(names, analysis.Competition)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AnalysisNames returns the names of every analysis, sorted and without duplicates
+func AnalysisNames() []string {
+	var names []string
+	for _, analysis := range analyses {
+		if !slices.Contains(names, analysis.Name) {
+			names = append(names, analysis.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupAnalysis returns the analysis of the given name for a competition
+func LookupAnalysis(competition, name string) (Analysis, error) {
+	if !slices.Contains(AnalysisCompetitionNames(), competition) {
+		return Analysis{}, fmt.Errorf("invalid competition %s. Valid options: %s", competition, strings.Join(AnalysisCompetitionNames(), ", "))
+	}
+
+	var names []string
+	for _, analysis := range Analyses() {
+		if analysis.Competition != competition {
+			continue
+		}
+		if analysis.Name == name {
+			return analysis, nil
+		}
+		names = append(names, analysis.Name)
+	}
+	return Analysis{}, fmt.Errorf("unsupported analysis type %s for %s. Valid options: %s", name, strings.ToUpper(competition), strings.Join(names, ", "))
+}
@@ -0,0 +1,181 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gamedl/internal/common"
+)
+
+type Config struct {
+	InputDir string
+	// Directories restricts the check to these competition directories of the input directory, every
+	// competition is checked when empty
+	Directories []string
+}
+
+// Problem is a game file that doesn't match its manifest entry
+type Problem struct {
+	Path string
+	// Kind is common.FileMissing, common.FileTruncated or common.FileModified
+	Kind string
+}
+
+// Result sums up the check of a dataset
+type Result struct {
+	Manifests int
+	Games     int
+	OK        int
+	// Unchecked are the games recorded before checksums were kept, only checked for being valid JSON
+	Unchecked int
+	// Untracked are the game files not recorded in the manifest of their season
+	Untracked int
+	// TempFiles are leftovers of interrupted writes
+	TempFiles []string
+	Problems  []Problem
+}
+
+func Run(config Config) error {
+	result, err := Dataset(config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Checked %d games in %d manifests\n", result.Games, result.Manifests)
+	fmt.Printf("  OK: %d\n", result.OK)
+	counts := make(map[string]int)
+	for _, problem := range result.Problems {
+		counts[problem.Kind]++
+	}
+	for _, kind := range []string{common.FileMissing, common.FileTruncated, common.FileModified} {
+		fmt.Printf("  %s: %d\n", strings.ToUpper(kind[:1])+kind[1:], counts[kind])
+	}
+	if result.Unchecked > 0 {
+		fmt.Printf("  Without checksum: %d\n", result.Unchecked)
+	}
+	if result.Untracked > 0 {
+		fmt.Printf("  Not in manifest: %d\n", result.Untracked)
+	}
+
+	if len(result.TempFiles) > 0 {
+		fmt.Printf("Leftovers of interrupted writes:\n")
+		for _, path := range result.TempFiles {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+
+	if len(result.Problems) > 0 {
+		fmt.Printf("Problems:\n")
+		for _, problem := range result.Problems {
+			fmt.Printf("  %-9s %s\n", problem.Kind, problem.Path)
+		}
+		return fmt.Errorf("found %d missing or damaged game files", len(result.Problems))
+	}
+
+	return nil
+}
+
+// Dataset checks every game file recorded in the manifests found under the input directory
+func Dataset(config Config) (*Result, error) {
+	result := &Result{}
+
+	err := filepath.WalkDir(config.InputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != config.InputDir && len(config.Directories) > 0 && filepath.Dir(path) == filepath.Clean(config.InputDir) &&
+				!slices.Contains(config.Directories, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") && strings.HasSuffix(d.Name(), ".tmp") {
+			result.TempFiles = append(result.TempFiles, path)
+			return nil
+		}
+		if d.Name() != ".manifest" {
+			return nil
+		}
+
+		return checkSeason(filepath.Dir(path), path, result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", config.InputDir, err)
+	}
+
+	return result, nil
+}
+
+// checkSeason checks the game files of a season directory against its manifest
+func checkSeason(seasonDir, manifestPath string, result *Result) error {
+	manifest, err := common.LoadManifestFile(manifestPath)
+	if err != nil {
+		return err
+	}
+	result.Manifests++
+
+	ids := make([]string, 0, len(manifest.Games))
+	for id := range manifest.Games {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	tracked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		entry := manifest.Games[id]
+		result.Games++
+
+		name := entry.Name
+		if name == "" {
+			name = id + ".json"
+		}
+		tracked[name] = true
+
+		if entry.SHA256 == "" {
+			kind := checkJSON(filepath.Join(seasonDir, name))
+			if kind != "" {
+				result.Problems = append(result.Problems, Problem{Path: filepath.Join(seasonDir, name), Kind: kind})
+				continue
+			}
+			result.Unchecked++
+			continue
+		}
+
+		if kind := entry.Check(seasonDir); kind != "" {
+			result.Problems = append(result.Problems, Problem{Path: filepath.Join(seasonDir, name), Kind: kind})
+			continue
+		}
+		result.OK++
+	}
+
+	entries, err := os.ReadDir(seasonDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && common.IsGameFile(entry.Name()) && !tracked[entry.Name()] {
+			result.Untracked++
+		}
+	}
+
+	return nil
+}
+
+// checkJSON tells whether a game file without checksum exists and holds a whole JSON document
+func checkJSON(path string) string {
+	data, err := common.ReadGameFile(path)
+	if os.IsNotExist(err) {
+		return common.FileMissing
+	}
+	if err != nil || !json.Valid(data) {
+		return common.FileTruncated
+	}
+	return ""
+}
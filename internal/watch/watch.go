@@ -0,0 +1,218 @@
+package watch
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"time"
+
+	"gamedl/internal/common"
+	"gamedl/lib/web/clients/usage"
+)
+
+// Game is a live game followed by the watcher
+type Game struct {
+	ID     string
+	Season common.Season
+	// Updated is the provider's change marker for the game in the schedule, recorded in the manifest once the game is final
+	Updated string
+}
+
+// Payload is a play by play fetched while watching a game
+type Payload struct {
+	Data   []byte
+	Status string
+	// Final is true once the game is over and its payload won't change anymore
+	Final bool
+	// Live is true while the game is in progress and its payload keeps changing
+	Live bool
+	// SourceURL is the provider url the payload was fetched from, without credentials
+	SourceURL string
+}
+
+// Source finds the live games of a competition and fetches their play by play
+type Source interface {
+	// LiveGames returns the selected games currently in progress, and next, the time the next
+	// selected game that hasn't started yet is scheduled at, zero when no game is left to start
+	LiveGames(ctx context.Context) (live []Game, next time.Time, err error)
+	// FetchPbp fetches the current play by play of a game
+	FetchPbp(ctx context.Context, game Game) (Payload, error)
+}
+
+type Options struct {
+	// Competition is the name of the competition directory the games are saved into
+	Competition string
+	Interval    time.Duration
+	OutputDir   string
+	Storage     common.Storage
+}
+
+type watchedGame struct {
+	Game
+	lastSum   [sha256.Size]byte
+	snapshots int
+}
+
+// Poll follows the live games of source until none is in progress anymore and none is left to start.
+// Every interval the schedule is checked for games that started, and the play by play of each
+// followed game is fetched again. While no game is in progress, the schedule is only checked again
+// when the next game is scheduled to start. Payloads that changed since the previous poll are saved as
+// timestamped snapshots next to the game file. Once a game is final its payload is saved as the
+// game file, it is recorded in the season manifest and the game isn't polled anymore.
+// The watch stops when the context is cancelled or the calls left are used up, the games left
+// unfinished keep their snapshots.
+func Poll(ctx context.Context, opts Options, source Source) error {
+	watched := make(map[string]*watchedGame)
+	done := make(map[string]bool)
+	var next time.Time
+
+	for {
+		live, upcoming, err := source.LiveGames(ctx)
+		if err != nil && ctx.Err() != nil {
+			return interrupted(ctx, watched)
+		}
+		if usage.OutOfCalls(err) {
+			return outOfCalls(err, watched)
+		}
+		if err != nil {
+			// A failed schedule poll shouldn't end the watch of the games already followed or to come
+			if len(watched) == 0 && next.IsZero() {
+				return fmt.Errorf("getting live games: %w", err)
+			}
+			fmt.Printf("Error getting live games: %v\n", err)
+		} else {
+			next = upcoming
+		}
+		for _, game := range live {
+			if _, ok := watched[game.ID]; ok || done[game.ID] {
+				continue
+			}
+			fmt.Printf("[%v] Watching game %s\n", game.Season, game.ID)
+			watched[game.ID] = &watchedGame{Game: game}
+		}
+
+		if len(watched) == 0 {
+			if next.IsZero() {
+				fmt.Println("No games in progress or left to start")
+				return nil
+			}
+			// A game scheduled in the past is late to start, so the schedule is checked at the usual interval
+			wait := max(opts.Interval, time.Until(next))
+			fmt.Printf("No games in progress, the next one is scheduled at %s\n", next.Local().Format(time.DateTime))
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return interrupted(ctx, watched)
+			}
+			continue
+		}
+
+		for id, game := range watched {
+			if ctx.Err() != nil {
+				return interrupted(ctx, watched)
+			}
+			stop, err := pollGame(ctx, opts, source, game)
+			if err != nil && ctx.Err() != nil {
+				return interrupted(ctx, watched)
+			}
+			if usage.OutOfCalls(err) {
+				return outOfCalls(err, watched)
+			}
+			if err != nil {
+				fmt.Printf("[%v] Error polling game %s: %v\n", game.Season, id, err)
+				continue
+			}
+			if stop {
+				delete(watched, id)
+				done[id] = true
+			}
+		}
+
+		select {
+		case <-time.After(opts.Interval):
+		case <-ctx.Done():
+			return interrupted(ctx, watched)
+		}
+	}
+}
+
+// interrupted reports the games still followed when the watch is cancelled
+func interrupted(ctx context.Context, watched map[string]*watchedGame) error {
+	fmt.Printf("Interrupted while watching %d games\n", len(watched))
+	printWatched(watched)
+	return fmt.Errorf("watch interrupted: %w", ctx.Err())
+}
+
+// outOfCalls reports the games still followed when the calls left are used up
+func outOfCalls(err error, watched map[string]*watchedGame) error {
+	fmt.Printf("Stopped, out of calls, while watching %d games\n", len(watched))
+	printWatched(watched)
+	return fmt.Errorf("watch stopped: %w", err)
+}
+
+func printWatched(watched map[string]*watchedGame) {
+	for id, game := range watched {
+		fmt.Printf("  [%v] %s: %d snapshots\n", game.Season, id, game.snapshots)
+	}
+}
+
+// pollGame fetches the play by play of a game, saving it if it changed, and reports whether the game should stop being polled
+func pollGame(ctx context.Context, opts Options, source Source, game *watchedGame) (bool, error) {
+	payload, err := source.FetchPbp(ctx, game.Game)
+	if err != nil {
+		return false, fmt.Errorf("fetching game pbp: %w", err)
+	}
+
+	sum := sha256.Sum256(payload.Data)
+	if sum != game.lastSum {
+		takenAt := time.Now()
+		snapshotDir := common.GetSnapshotDirectoryPath(opts.OutputDir, opts.Competition, game.Season.Year, game.Season.Type, game.ID)
+		if err := os.MkdirAll(snapshotDir, 0o755); err != nil {
+			return false, fmt.Errorf("creating snapshot directory: %w", err)
+		}
+
+		snapshotFile := common.GetSnapshotFilePath(opts.OutputDir, opts.Competition, game.Season.Year, game.Season.Type, game.ID, takenAt)
+		if _, err := common.WriteGameFile(snapshotFile, payload.Data, opts.Storage); err != nil {
+			return false, err
+		}
+		game.lastSum = sum
+		game.snapshots++
+		fmt.Printf("[%v] Game %s changed (status %s), saved snapshot %d\n", game.Season, game.ID, payload.Status, game.snapshots)
+	}
+
+	if payload.Final {
+		gameFile := common.GetGameFilePath(opts.OutputDir, opts.Competition, game.Season.Year, game.Season.Type, game.ID)
+		savedFile, err := common.WriteGameFile(gameFile, payload.Data, opts.Storage)
+		if err != nil {
+			return false, err
+		}
+		savedFile.SourceURL = payload.SourceURL
+
+		manifest, err := common.LoadManifest(opts.OutputDir, opts.Competition, game.Season)
+		if err != nil {
+			return false, fmt.Errorf("loading manifest: %w", err)
+		}
+		manifest.Record(common.ManifestEntry{
+			ID:        game.ID,
+			Status:    payload.Status,
+			Updated:   game.Updated,
+			Final:     true,
+			FetchedAt: time.Now(),
+			GameFile:  savedFile,
+		})
+		if err := manifest.Save(); err != nil {
+			return false, err
+		}
+
+		fmt.Printf("[%v] Game %s is final after %d snapshots\n", game.Season, game.ID, game.snapshots)
+		return true, nil
+	}
+
+	if !payload.Live {
+		fmt.Printf("[%v] Game %s is %s, no longer watching it\n", game.Season, game.ID, payload.Status)
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,72 @@
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gamedl/internal/common"
+)
+
+// scriptedSource plays a game that starts after the first schedule poll,
+// is in progress for one poll and then final
+type scriptedSource struct {
+	schedulePolls int
+	pbpFetches    int
+}
+
+func (s *scriptedSource) LiveGames(context.Context) ([]Game, time.Time, error) {
+	s.schedulePolls++
+	game := Game{ID: "1", Season: common.Season{Year: 2024, Type: common.RegularSeason}}
+	if s.schedulePolls == 1 {
+		return nil, time.Now(), nil
+	}
+	if s.pbpFetches < 2 {
+		return []Game{game}, time.Time{}, nil
+	}
+	return nil, time.Time{}, nil
+}
+
+func (s *scriptedSource) FetchPbp(context.Context, Game) (Payload, error) {
+	s.pbpFetches++
+	if s.pbpFetches == 1 {
+		return Payload{Data: []byte(`{"status":"inprogress"}`), Status: "inprogress", Live: true}, nil
+	}
+	return Payload{Data: []byte(`{"status":"closed"}`), Status: "closed", Final: true}, nil
+}
+
+func TestPollWaitsForGamesLeftToStart(t *testing.T) {
+	dir := t.TempDir()
+	source := &scriptedSource{}
+	opts := Options{Competition: "NBA", Interval: time.Millisecond, OutputDir: dir}
+
+	if err := Poll(context.Background(), opts, source); err != nil {
+		t.Fatalf("Poll returned an error: %v", err)
+	}
+
+	if source.pbpFetches != 2 {
+		t.Errorf("fetched the game %d times, want 2: while in progress, then once final", source.pbpFetches)
+	}
+	season := common.Season{Year: 2024, Type: common.RegularSeason}
+	if !common.GameFileExists(common.GetGameFilePath(dir, "NBA", season.Year, season.Type, "1")) {
+		t.Error("Poll ended without saving the final game file")
+	}
+	manifest, err := common.LoadManifest(dir, "NBA", season)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := manifest.Games["1"]; entry == nil || !entry.Final {
+		t.Errorf("manifest entry = %+v, want the game recorded final", entry)
+	}
+}
+
+func TestPollEndsWithoutGames(t *testing.T) {
+	source := &scriptedSource{schedulePolls: 1, pbpFetches: 2}
+	opts := Options{Competition: "NBA", Interval: time.Hour, OutputDir: t.TempDir()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := Poll(ctx, opts, source); err != nil {
+		t.Fatalf("Poll returned %v, want it to end right away", err)
+	}
+}
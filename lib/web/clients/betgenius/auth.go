@@ -2,12 +2,14 @@ package betgenius
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"sync"
 	"time"
+
+	"gamedl/lib/web/clients/usage"
 )
 
 type AuthV1Reply struct {
@@ -71,7 +73,7 @@ func (t *TokenV1) IsExpired() bool {
 	return time.Now().After(*t.ExpiresIn)
 }
 
-func (c *Client) GetV1Token() (string, error) {
+func (c *Client) GetV1Token(ctx context.Context) (string, error) {
 	c.v1Token.m.RLock()
 	if c.v1Token != nil && !c.v1Token.IsExpired() {
 		c.v1Token.m.RUnlock()
@@ -89,9 +91,8 @@ func (c *Client) GetV1Token() (string, error) {
 		return "", fmt.Errorf("could not marshal auth: %w", err)
 	}
 
-	buff := bytes.NewBuffer(payload)
-
-	req, err := http.NewRequest("POST", c.authV1, buff)
+	// A bytes.Reader body can be rewound, so the login is retried like the other requests
+	req, err := http.NewRequestWithContext(usage.WithCall(ctx, c.fixturesCall("auth")), "POST", c.authV1, bytes.NewReader(payload))
 	if err != nil {
 		return "", fmt.Errorf("could not create request: %w", err)
 	}
@@ -102,18 +103,13 @@ func (c *Client) GetV1Token() (string, error) {
 	req.Header.Set("Connection", "keep-alive")
 	req.Header.Set("User-Agent", "PostmanRuntime/7.26.8")
 
-	r, err := c.client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer r.Body.Close()
-
 	startExp := time.Now()
-	reply := &AuthV1Reply{}
-	replyData, err := io.ReadAll(r.Body)
+	// Non 2xx replies are returned as a retry.StatusError, so no token is cached from them
+	replyData, err := c.retryPolicy.Do(c.client, req)
 	if err != nil {
-		return "", fmt.Errorf("could not read auth v1 reply: %w", err)
+		return "", fmt.Errorf("could not get auth v1 token: %w", err)
 	}
+	reply := &AuthV1Reply{}
 	if err := json.Unmarshal(replyData, reply); err != nil {
 		return "", fmt.Errorf("could not unmarshal auth v1 reply: %w", err)
 	}
@@ -130,7 +126,7 @@ func (c *Client) GetV1Token() (string, error) {
 	return reply.IDToken, nil
 }
 
-func (c *Client) GetOAuthToken() (string, error) {
+func (c *Client) GetOAuthToken(ctx context.Context) (string, error) {
 	c.oAuthToken.m.RLock()
 	if c.oAuthToken != nil && !c.oAuthToken.IsExpired() {
 		c.oAuthToken.m.RUnlock()
@@ -138,29 +134,22 @@ func (c *Client) GetOAuthToken() (string, error) {
 	}
 	c.oAuthToken.m.RUnlock()
 
-	req, err := http.NewRequest("POST", c.authOauth, nil)
+	req, err := http.NewRequestWithContext(usage.WithCall(ctx, c.statsCall("auth")), "POST", c.authOauth, nil)
 	if err != nil {
 		return "", fmt.Errorf("could not create request: %w", err)
 	}
 
 	req.SetBasicAuth(c.statsUsername, c.statsPassword)
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
 	startExp := time.Now()
-	reply := &OAuthReply{}
-
-	replyData, err := io.ReadAll(resp.Body)
+	replyData, err := c.retryPolicy.Do(c.client, req)
 	if err != nil {
-		return "", fmt.Errorf("could not read auth v1 reply: %w", err)
+		return "", fmt.Errorf("could not get oauth token: %w", err)
 	}
 
+	reply := &OAuthReply{}
 	if err = json.Unmarshal(replyData, reply); err != nil {
-		return "", fmt.Errorf("could not unmarshal auth v1 reply: %w", err)
+		return "", fmt.Errorf("could not unmarshal oauth reply: %w", err)
 	}
 
 	c.oAuthToken.m.Lock()
@@ -175,13 +164,13 @@ func (c *Client) GetOAuthToken() (string, error) {
 	return reply.AccessToken, nil
 }
 
-func (c *Client) GetV1AuthedRequest(url string) (*http.Request, error) {
-	token, err := c.GetV1Token()
+func (c *Client) GetV1AuthedRequest(ctx context.Context, url string) (*http.Request, error) {
+	token, err := c.GetV1Token(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not create request: %w", err)
 	}
@@ -192,13 +181,13 @@ func (c *Client) GetV1AuthedRequest(url string) (*http.Request, error) {
 	return req, nil
 }
 
-func (c *Client) GetOAuthAuthedRequest(url string) (*http.Request, error) {
-	token, err := c.GetOAuthToken()
+func (c *Client) GetOAuthAuthedRequest(ctx context.Context, url string) (*http.Request, error) {
+	token, err := c.GetOAuthToken(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not create request: %w", err)
 	}
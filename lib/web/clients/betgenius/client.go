@@ -1,6 +1,22 @@
 package betgenius
 
-import "net/http"
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gamedl/lib/web/clients/httpclient"
+	"gamedl/lib/web/clients/ratelimit"
+	"gamedl/lib/web/clients/retry"
+	"gamedl/lib/web/clients/usage"
+)
+
+// Sport ids of the Genius fixtures and match state APIs
+const (
+	BasketballSportID       = 4
+	AmericanFootballSportID = 17
+)
 
 type ClientOption func(*Client)
 
@@ -40,8 +56,99 @@ func WithStatsPassword(password string) ClientOption {
 	}
 }
 
+// WithRateLimits limits the requests per second sent to each endpoint class.
+// The limits are shared by every goroutine using the client.
+func WithRateLimits(rates ratelimit.Rates) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.RateLimits = rates
+	}
+}
+
+// WithUsage counts the requests sent with each api key, and refuses the ones the recorder doesn't allow
+func WithUsage(recorder usage.Recorder) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.Usage = recorder
+	}
+}
+
+// WithBaseURL sends every request to another host, e.g. a local fake server. The paths and
+// queries of the requests are unchanged.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(client *Client) {
+		for _, u := range []*string{&client.authV1, &client.authOauth, &client.fixturesV1URL, &client.fixturesV2URL} {
+			*u = rebase(*u, baseURL)
+		}
+	}
+}
+
+// WithAuthV1URL sets the url of the fixtures API login
+func WithAuthV1URL(authURL string) ClientOption {
+	return func(client *Client) {
+		client.authV1 = authURL
+	}
+}
+
+// WithOAuthURL sets the url of the match state API token request, grant type and scopes included
+func WithOAuthURL(authURL string) ClientOption {
+	return func(client *Client) {
+		client.authOauth = authURL
+	}
+}
+
+// WithFixturesV1URL sets the base url of the fixtures API
+func WithFixturesV1URL(baseURL string) ClientOption {
+	return func(client *Client) {
+		client.fixturesV1URL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithFixturesV2URL sets the base url of the match state API
+func WithFixturesV2URL(baseURL string) ClientOption {
+	return func(client *Client) {
+		client.fixturesV2URL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport sends the requests with transport instead of a default one
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.Transport = transport
+	}
+}
+
+// WithTimeout limits the time of each attempt of a request, reading its body included
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.Timeout = timeout
+	}
+}
+
+// WithProxy sends the requests through a proxy instead of the one of the environment.
+// It has no effect with WithTransport.
+func WithProxy(proxy *url.URL) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.Proxy = proxy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests
+func WithUserAgent(userAgent string) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.UserAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried
+func WithRetryPolicy(policy retry.Policy) ClientOption {
+	return func(client *Client) {
+		client.retryPolicy = policy
+	}
+}
+
 type Client struct {
-	client *http.Client
+	client      *http.Client
+	httpOptions httpclient.Options
+	retryPolicy retry.Policy
 
 	fixtureKey      string
 	fixtureUsername string
@@ -63,11 +170,11 @@ type Client struct {
 
 func NewClient(options ...ClientOption) *Client {
 	client := &Client{
-		client:        &http.Client{},
+		retryPolicy:   retry.DefaultPolicy(),
 		authV1:        "https://api.geniussports.com/Auth-v1/PROD/login",
 		authOauth:     "https://auth.api.geniussports.com/oauth2/token?grant_type=client_credentials&scope=statistics-api%2Fstatistics%3Aread%20statistics-api%2Fliveaccess%3Aread%20matchstateapi%2Fmatchstate%3Aread%20matchstateapi%2Fgranularity%3Aread",
 		fixturesV1URL: "https://api.geniussports.com/Fixtures-v1/PRODPRM",
-		fixturesV2URL: "https://platform.matchstate.api.geniussports.com/api/v2/sources/GeniusPremium/sports/17",
+		fixturesV2URL: "https://platform.matchstate.api.geniussports.com/api/v2/sources/GeniusPremium",
 		v1Token:       NewTokenV1(),
 		oAuthToken:    NewOAuthToken(),
 	}
@@ -75,6 +182,26 @@ func NewClient(options ...ClientOption) *Client {
 	for _, option := range options {
 		option(client)
 	}
+	client.client = httpclient.New(client.httpOptions)
 
 	return client
 }
+
+// rebase replaces the scheme and host of rawURL with the ones of baseURL, which may add a path prefix
+func rebase(rawURL, baseURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + u.RequestURI()
+}
+
+// fixturesCall identifies a request made to an endpoint of the fixtures API with the fixture key
+func (c *Client) fixturesCall(endpoint string) usage.Call {
+	return usage.Call{Credential: "betgenius/fixtures", KeyID: usage.KeyID(c.fixtureKey), Endpoint: endpoint}
+}
+
+// statsCall identifies a request made to an endpoint of the match state API with the stats key
+func (c *Client) statsCall(endpoint string) usage.Call {
+	return usage.Call{Credential: "betgenius/stats", KeyID: usage.KeyID(c.statsKey), Endpoint: endpoint}
+}
@@ -0,0 +1,28 @@
+package betgenius
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gamedl/lib/web/clients/ratelimit"
+)
+
+func (c *Client) GetCompetitionsRaw(ctx context.Context, sportID int) ([]byte, error) {
+	url := fmt.Sprintf("%s/sports/%d/competitions", c.fixturesV1URL, sportID)
+	return c.doV1Request(ctx, "competitions", ratelimit.Schedule, url)
+}
+
+func (c *Client) GetCompetitions(ctx context.Context, sportID int) (*CompetitionsReply, error) {
+	raw, err := c.GetCompetitionsRaw(ctx, sportID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get competitions of sport %d: %w", sportID, err)
+	}
+
+	r := &CompetitionsReply{}
+	if err := json.Unmarshal(raw, r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal competitions of sport %d: %w", sportID, err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,123 @@
+package betgenius
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gamedl/lib/web/clients/ratelimit"
+	"gamedl/lib/web/clients/usage"
+)
+
+// The fixtures and match state APIs are the same for every competition: the seasons and fixtures
+// of a competition only differ by its id, and the match states by the sport of the fixture.
+// competition names the competition in the usage endpoints and the errors, e.g. nfl.
+
+func (c *Client) getSeasonsRaw(ctx context.Context, competition, compId string) ([]byte, error) {
+	url := fmt.Sprintf("%s/competitions/%s/seasons", c.fixturesV1URL, compId)
+	return c.doV1Request(ctx, competition+"/seasons", ratelimit.Schedule, url)
+}
+
+func (c *Client) getSeasons(ctx context.Context, competition, compId string) (*SeasonsReply, error) {
+	raw, err := c.getSeasonsRaw(ctx, competition, compId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get %s seasons: %w", competition, err)
+	}
+
+	r := &SeasonsReply{}
+	if err := json.Unmarshal(raw, r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s seasons: %w", competition, err)
+	}
+
+	return r, nil
+}
+
+func (c *Client) getGamesForSeasonRaw(ctx context.Context, competition string, seasonID int) ([]byte, error) {
+	url := fmt.Sprintf("%s/seasons/%d/fixtures", c.fixturesV1URL, seasonID)
+	return c.doV1Request(ctx, competition+"/fixtures", ratelimit.Schedule, url)
+}
+
+func (c *Client) getGamesForSeason(ctx context.Context, competition string, seasonID int) (*GamesOfSeason, error) {
+	raw, err := c.getGamesForSeasonRaw(ctx, competition, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get %s games for season: %w", competition, err)
+	}
+
+	r := &GamesOfSeason{}
+	if err := json.Unmarshal(raw, r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s games for season: %w", competition, err)
+	}
+
+	return r, nil
+}
+
+// pbpURL returns the url of the match state of a fixture of a sport
+func (c *Client) pbpURL(sportID int, gameID string) string {
+	return fmt.Sprintf("%s/sports/%d/fixtures/%s", c.fixturesV2URL, sportID, gameID)
+}
+
+func (c *Client) getPbpRaw(ctx context.Context, competition string, sportID int, gameID string) ([]byte, error) {
+	return c.doOAuthRequest(ctx, competition+"/pbp", ratelimit.Pbp, c.pbpURL(sportID, gameID))
+}
+
+func (c *Client) openPbp(ctx context.Context, competition string, sportID int, gameID string) (io.ReadCloser, error) {
+	return c.openOAuthRequest(ctx, competition+"/pbp", ratelimit.Pbp, c.pbpURL(sportID, gameID))
+}
+
+// getPbp fetches the match state of a fixture and unmarshals it into the model P of its sport
+func getPbp[P any](ctx context.Context, c *Client, competition string, sportID int, gameID string) (*P, error) {
+	raw, err := c.getPbpRaw(ctx, competition, sportID, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get %s play by play: %w", competition, err)
+	}
+
+	r := new(P)
+	if err := json.Unmarshal(raw, r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s play by play: %w", competition, err)
+	}
+
+	return r, nil
+}
+
+func (c *Client) doV1Request(ctx context.Context, endpoint string, class ratelimit.Class, url string) ([]byte, error) {
+	r, err := c.GetV1AuthedRequest(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not get authed request: %w", err)
+	}
+	ctx = usage.WithCall(ratelimit.WithClass(r.Context(), class), c.fixturesCall(endpoint))
+	return c.doAndReadRequest(r.WithContext(ctx))
+}
+
+func (c *Client) doOAuthRequest(ctx context.Context, endpoint string, class ratelimit.Class, url string) ([]byte, error) {
+	r, err := c.GetOAuthAuthedRequest(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not get authed request: %w", err)
+	}
+	ctx = usage.WithCall(ratelimit.WithClass(r.Context(), class), c.statsCall(endpoint))
+	return c.doAndReadRequest(r.WithContext(ctx))
+}
+
+// openOAuthRequest sends a match state request and returns the body of the reply unread so it can be streamed
+func (c *Client) openOAuthRequest(ctx context.Context, endpoint string, class ratelimit.Class, url string) (io.ReadCloser, error) {
+	r, err := c.GetOAuthAuthedRequest(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not get authed request: %w", err)
+	}
+	ctx = usage.WithCall(ratelimit.WithClass(r.Context(), class), c.statsCall(endpoint))
+	body, err := c.retryPolicy.Open(c.client, r.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("could not do request: %w", err)
+	}
+	return body, nil
+}
+
+func (c *Client) doAndReadRequest(r *http.Request) ([]byte, error) {
+	body, err := c.retryPolicy.Do(c.client, r)
+	if err != nil {
+		return nil, fmt.Errorf("could not do request: %w", err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,40 @@
+package betgenius
+
+import (
+	"context"
+	"io"
+)
+
+func (c *Client) GetNcaabSeasonsRaw(ctx context.Context, compId string) ([]byte, error) {
+	return c.getSeasonsRaw(ctx, "ncaab", compId)
+}
+
+func (c *Client) GetNcaabSeasons(ctx context.Context, compId string) (*SeasonsReply, error) {
+	return c.getSeasons(ctx, "ncaab", compId)
+}
+
+func (c *Client) GetNcaabGamesForSeasonRaw(ctx context.Context, seasonID int) ([]byte, error) {
+	return c.getGamesForSeasonRaw(ctx, "ncaab", seasonID)
+}
+
+func (c *Client) GetNcaabGamesForSeason(ctx context.Context, seasonID int) (*GamesOfSeason, error) {
+	return c.getGamesForSeason(ctx, "ncaab", seasonID)
+}
+
+// NcaabPbpURL returns the url of the match state of a basketball fixture
+func (c *Client) NcaabPbpURL(gameID string) string {
+	return c.pbpURL(BasketballSportID, gameID)
+}
+
+func (c *Client) GetNcaabPbpRaw(ctx context.Context, gameID string) ([]byte, error) {
+	return c.getPbpRaw(ctx, "ncaab", BasketballSportID, gameID)
+}
+
+// OpenNcaabPbp streams the play by play of a fixture. The caller must close the returned body.
+func (c *Client) OpenNcaabPbp(ctx context.Context, gameID string) (io.ReadCloser, error) {
+	return c.openPbp(ctx, "ncaab", BasketballSportID, gameID)
+}
+
+func (c *Client) GetNcaabPbp(ctx context.Context, gameID string) (*BasketballGamePbp, error) {
+	return getPbp[BasketballGamePbp](ctx, c, "ncaab", BasketballSportID, gameID)
+}
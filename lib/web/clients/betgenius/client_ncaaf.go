@@ -0,0 +1,40 @@
+package betgenius
+
+import (
+	"context"
+	"io"
+)
+
+func (c *Client) GetNcaafSeasonsRaw(ctx context.Context, compId string) ([]byte, error) {
+	return c.getSeasonsRaw(ctx, "ncaaf", compId)
+}
+
+func (c *Client) GetNcaafSeasons(ctx context.Context, compId string) (*SeasonsReply, error) {
+	return c.getSeasons(ctx, "ncaaf", compId)
+}
+
+func (c *Client) GetNcaafGamesForSeasonRaw(ctx context.Context, seasonID int) ([]byte, error) {
+	return c.getGamesForSeasonRaw(ctx, "ncaaf", seasonID)
+}
+
+func (c *Client) GetNcaafGamesForSeason(ctx context.Context, seasonID int) (*GamesOfSeason, error) {
+	return c.getGamesForSeason(ctx, "ncaaf", seasonID)
+}
+
+// NcaafPbpURL returns the url of the match state of a college football fixture, which has the shape of the NFL one
+func (c *Client) NcaafPbpURL(gameID string) string {
+	return c.pbpURL(AmericanFootballSportID, gameID)
+}
+
+func (c *Client) GetNcaafPbpRaw(ctx context.Context, gameID string) ([]byte, error) {
+	return c.getPbpRaw(ctx, "ncaaf", AmericanFootballSportID, gameID)
+}
+
+// OpenNcaafPbp streams the play by play of a fixture. The caller must close the returned body.
+func (c *Client) OpenNcaafPbp(ctx context.Context, gameID string) (io.ReadCloser, error) {
+	return c.openPbp(ctx, "ncaaf", AmericanFootballSportID, gameID)
+}
+
+func (c *Client) GetNcaafPbp(ctx context.Context, gameID string) (*GamePbp, error) {
+	return getPbp[GamePbp](ctx, c, "ncaaf", AmericanFootballSportID, gameID)
+}
@@ -0,0 +1,47 @@
+package betgenius
+
+import (
+	"strconv"
+	"strings"
+)
+
+type CompetitionsReply struct {
+	Total    int `json:"total"`
+	Links    `json:"_links"`
+	Embedded struct {
+		Competitions []*Competition `json:"competitions"`
+	} `json:"_embedded"`
+}
+
+// Find returns the competition whose id or name matches the given one, ignoring case
+func (r *CompetitionsReply) Find(idOrName string) *Competition {
+	for _, competition := range r.Embedded.Competitions {
+		if strconv.Itoa(competition.ID) == idOrName || strings.EqualFold(competition.Name, idOrName) {
+			return competition
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the competitions
+func (r *CompetitionsReply) Names() []string {
+	names := make([]string, 0, len(r.Embedded.Competitions))
+	for _, competition := range r.Embedded.Competitions {
+		names = append(names, competition.Name)
+	}
+	return names
+}
+
+type Competition struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	SportID    int    `json:"sportId"`
+	SportName  string `json:"sportName"`
+	RegionID   int    `json:"regionId"`
+	RegionName string `json:"regionName"`
+	Gender     string `json:"gender"`
+	Updates    int    `json:"updates"`
+	Deleted    bool   `json:"deleted"`
+	LastUpdate string `json:"lastUpdate"`
+	Links      `json:"_links"`
+}
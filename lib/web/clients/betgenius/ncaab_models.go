@@ -0,0 +1,75 @@
+package betgenius
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BasketballGamePbp is the match state of a basketball fixture
+type BasketballGamePbp struct {
+	Periods     []BasketballPeriod `json:"periods"`
+	MatchStatus string             `json:"matchStatus"`
+	Period      struct {
+		Number     int  `json:"number"`
+		IsOvertime bool `json:"isOvertime"`
+	} `json:"period"`
+	GameTime struct {
+		Clock      string `json:"clock"`
+		IsRunning  bool   `json:"isRunning"`
+		LastUpdate string `json:"lastUpdate"`
+	} `json:"gameTime"`
+	Score struct {
+		Home int `json:"home"`
+		Away int `json:"away"`
+	} `json:"score"`
+	HomeTeam BasketballTeam `json:"homeTeam"`
+	AwayTeam BasketballTeam `json:"awayTeam"`
+	Comments []struct {
+		Text string `json:"text"`
+	} `json:"comments"`
+	Source              string     `json:"source"`
+	FixtureID           string     `json:"fixtureId"`
+	Sequence            int        `json:"sequence"`
+	MessageTimestampUtc *time.Time `json:"messageTimestampUtc"`
+	IsReliable          bool       `json:"isReliable"`
+	IsCoverageCancelled bool       `json:"isCoverageCancelled"`
+}
+
+type BasketballTeam struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	TimeoutsRemaining int    `json:"timeoutsRemaining"`
+	Fouls             int    `json:"fouls"`
+	IsInBonus         bool   `json:"isInBonus"`
+	Lineup            []struct {
+		PlayerID string `json:"playerId"`
+		Name     string `json:"name"`
+		Number   string `json:"number"`
+		OnCourt  bool   `json:"onCourt"`
+	} `json:"lineup"`
+}
+
+type BasketballPeriod struct {
+	Number     int                `json:"number"`
+	IsOvertime bool               `json:"isOvertime"`
+	IsFinished bool               `json:"isFinished"`
+	Actions    []BasketballAction `json:"actions"`
+}
+
+type BasketballAction struct {
+	ID           string          `json:"id"`
+	Type         string          `json:"type"`
+	SubType      string          `json:"subType"`
+	Team         string          `json:"team"`
+	PlayerID     string          `json:"playerId"`
+	Sequence     int             `json:"sequence"`
+	GameTime     string          `json:"gameTime"`
+	Points       int             `json:"points"`
+	HomeScore    int             `json:"homeScore"`
+	AwayScore    int             `json:"awayScore"`
+	IsConfirmed  bool            `json:"isConfirmed"`
+	IsVoid       bool            `json:"isVoid"`
+	Description  string          `json:"description"`
+	TimestampUtc *time.Time      `json:"timestampUtc"`
+	Details      json.RawMessage `json:"details"`
+}
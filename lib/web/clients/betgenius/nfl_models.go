@@ -3,6 +3,7 @@ package betgenius
 import (
 	"encoding/json"
 	"slices"
+	"strconv"
 	"time"
 )
 
@@ -158,7 +159,26 @@ type Fixture struct {
 	Links      `json:"_links"`
 }
 
+// StartTime parses the start date of the fixture, given in UTC
+func (f *Fixture) StartTime() (time.Time, error) {
+	return time.Parse("2006-01-02 15:04:05", f.StartDate)
+}
+
+// CompetitorIdentifiers returns the ids, names and short names of the competitors of the fixture
+func (f *Fixture) CompetitorIdentifiers() []string {
+	identifiers := make([]string, 0, len(f.Fixturecompetitors)*3)
+	for _, fc := range f.Fixturecompetitors {
+		identifiers = append(identifiers,
+			strconv.Itoa(fc.Competitor.ID),
+			fc.Competitor.Name,
+			fc.Competitor.Teamproperty.ShortName,
+		)
+	}
+	return identifiers
+}
+
 type Drive struct {
+	ID               string `json:"id"`
 	TeamInPossession string `json:"teamInPossession"`
 	IsKickOff        bool   `json:"isKickOff"`
 	Plays            []struct {
@@ -0,0 +1,219 @@
+package cassette
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gamedl/lib/web/clients/retry"
+)
+
+// Redacted replaces the secrets of the recorded requests and responses
+const Redacted = "REDACTED"
+
+// Interaction is a request and the response it got, as saved in a cassette
+type Interaction struct {
+	// Seq orders the interactions of the same request, starting at 1
+	Seq      int      `json:"seq"`
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request, its body included since BetGenius logins are POST requests
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response, its body decompressed
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+	// BodyEncoding is base64 when the body isn't text, empty otherwise
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// body returns the bytes of the response body
+func (r Response) body() ([]byte, error) {
+	if r.BodyEncoding == "base64" {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+	return []byte(r.Body), nil
+}
+
+func setBody(r *Response, body []byte) {
+	if utf8.Valid(body) {
+		r.Body = string(body)
+		return
+	}
+	r.Body = base64.StdEncoding.EncodeToString(body)
+	r.BodyEncoding = "base64"
+}
+
+var (
+	// secretParams are the query parameters redacted from the urls, e.g. the SportRadar api_key
+	secretParams = regexp.MustCompile(`(?i)key|token|secret|password|signature`)
+	// secretHeaders are the headers redacted from requests and responses, on top of the ones of sensitiveHeaders
+	secretHeaders    = regexp.MustCompile(`(?i)key|token|secret|auth`)
+	sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+	// secretRequestFields are the fields redacted from JSON request bodies, e.g. the BetGenius login
+	secretRequestFields = regexp.MustCompile(`(?i)user|pass|secret|token|key`)
+	// secretResponseFields are the fields redacted from JSON response bodies, e.g. the BetGenius tokens.
+	// They are fewer than the request ones, so the payloads of the games are kept as they are.
+	secretResponseFields = regexp.MustCompile(`(?i)token|secret|password`)
+)
+
+// matchKey identifies the requests a recorded response is served to: their method and url, without
+// the secret query parameters, so recordings of keys sent in the url match requests sending them as headers
+func matchKey(method string, u *url.URL) string {
+	matched := *u
+	matched.User = nil
+	query := matched.Query()
+	for name := range query {
+		if secretParams.MatchString(name) {
+			query.Del(name)
+		}
+	}
+	matched.RawQuery = query.Encode()
+	return method + " " + matched.String()
+}
+
+// redactURL returns u with the values of its secret query parameters redacted, and its parameters sorted
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	query := redacted.Query()
+	for name := range query {
+		if secretParams.MatchString(name) {
+			query[name] = []string{Redacted}
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for name := range redacted {
+		if secretHeaders.MatchString(name) || containsFold(sensitiveHeaders, name) {
+			redacted[name] = []string{Redacted}
+		}
+	}
+	return redacted
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJSON redacts the string values of the fields matching secrets, at any depth. Bodies without
+// such fields are returned as they are, and bodies that aren't JSON are redacted as a whole.
+func redactJSON(body []byte, secrets *regexp.Regexp) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []byte(Redacted)
+	}
+	if !redactValue(value, secrets) {
+		return body
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return []byte(Redacted)
+	}
+	return redacted
+}
+
+// redactValue redacts the secret fields of a decoded JSON value, reporting whether any was found
+func redactValue(value any, secrets *regexp.Regexp) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]any:
+		for name, field := range v {
+			if _, ok := field.(string); ok && secrets.MatchString(name) {
+				v[name] = Redacted
+				redacted = true
+				continue
+			}
+			redacted = redactValue(field, secrets) || redacted
+		}
+	case []any:
+		for _, item := range v {
+			redacted = redactValue(item, secrets) || redacted
+		}
+	}
+	return redacted
+}
+
+// decodeBody returns the body of a response decompressed, and its header without Content-Encoding,
+// so it can be redacted. Only gzip is supported, the encodings Go decompresses itself aside.
+func decodeBody(header http.Header, body []byte) (http.Header, []byte, error) {
+	encoding := header.Get("Content-Encoding")
+	if encoding == "" || encoding == "identity" {
+		return header, body, nil
+	}
+	if encoding != "gzip" {
+		return nil, nil, fmt.Errorf("can't redact a %s encoded response", encoding)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("decompressing response: %w", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decompressing response: %w", err)
+	}
+	header = header.Clone()
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+	return header, decoded, nil
+}
+
+// fileName returns the name of the file of an interaction, readable and unique within a cassette
+func fileName(key string, u *url.URL, seq int) string {
+	slug := strings.Trim(nonWord.ReplaceAllString(u.Path, "-"), "-")
+	if len(slug) > 80 {
+		slug = slug[len(slug)-80:]
+	}
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%s-%s-%d.json", slug, hex.EncodeToString(sum[:4]), seq)
+}
+
+var nonWord = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+
+// UnmatchedError is returned when a cassette has no response recorded for a request. It is
+// never retried, since replaying the request can't find one either.
+type UnmatchedError struct {
+	Method string
+	URL    string
+}
+
+func (e *UnmatchedError) Error() string {
+	return fmt.Sprintf("cassette has no response recorded for %s %s", e.Method, e.URL)
+}
+
+func (e *UnmatchedError) Is(target error) bool {
+	return target == retry.ErrPermanent
+}
@@ -0,0 +1,183 @@
+package cassette
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gamedl/lib/web/clients/retry"
+)
+
+// newProviderServer answers like a provider: a login returning a gzipped token, and game
+// payloads answering 503 to the first request of each game
+func newProviderServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), "hunter2") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(`{"IdToken":"secret-token","ExpiresIn":3600}`))
+			gz.Close()
+		case "/games/1/pbp.json":
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"id":"1","status":"closed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// send sends a request with the client and returns the status code and body of its response
+func send(t *testing.T, client *http.Client, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Api-Key", "key-header")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data)
+}
+
+// record records a login and two requests of a game payload, the first one failing, into dir
+func record(t *testing.T, dir, serverURL string) {
+	t.Helper()
+	recorder, err := NewRecorder(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+
+	if status, body := send(t, client, http.MethodPost, serverURL+"/login", `{"user":"me","password":"hunter2"}`); status != http.StatusOK || !strings.Contains(body, "secret-token") {
+		t.Fatalf("login recorded %d %s, want the token returned as received", status, body)
+	}
+	if status, _ := send(t, client, http.MethodGet, serverURL+"/games/1/pbp.json?api_key=sr-key", ""); status != http.StatusServiceUnavailable {
+		t.Fatalf("first pbp request recorded %d, want 503", status)
+	}
+	if status, _ := send(t, client, http.MethodGet, serverURL+"/games/1/pbp.json?api_key=sr-key", ""); status != http.StatusOK {
+		t.Fatalf("second pbp request recorded %d, want 200", status)
+	}
+}
+
+func TestRecordRedactsSecrets(t *testing.T) {
+	server := newProviderServer(t)
+	dir := t.TempDir()
+	record(t, dir, server.URL)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 3 {
+		t.Fatalf("cassette holds %d interactions (%v), want 3", len(files), err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"sr-key", "key-header", "hunter2", "secret-token"} {
+			if bytes.Contains(data, []byte(secret)) {
+				t.Errorf("%s holds the secret %q", filepath.Base(file), secret)
+			}
+		}
+	}
+}
+
+func TestRecorderRefusesRecordedCassette(t *testing.T) {
+	server := newProviderServer(t)
+	dir := t.TempDir()
+	record(t, dir, server.URL)
+
+	if _, err := NewRecorder(dir, nil); err == nil {
+		t.Error("NewRecorder accepted a directory holding a cassette")
+	}
+}
+
+func TestReplayServesRecordedResponsesInOrder(t *testing.T) {
+	server := newProviderServer(t)
+	dir := t.TempDir()
+	record(t, dir, server.URL)
+	server.Close()
+
+	replayer, err := LoadReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: replayer}
+
+	// Requests match whatever their secrets, since they are redacted on both sides
+	if status, body := send(t, client, http.MethodPost, server.URL+"/login", `{"user":"other","password":"other"}`); status != http.StatusOK || !strings.Contains(body, Redacted) {
+		t.Errorf("replayed login = %d %s, want 200 with the token redacted", status, body)
+	}
+	pbpURL := server.URL + "/games/1/pbp.json?api_key=other-key"
+	if status, _ := send(t, client, http.MethodGet, pbpURL, ""); status != http.StatusServiceUnavailable {
+		t.Errorf("first replayed pbp = %d, want the recorded 503", status)
+	}
+	// Keys sent as headers rather than in the url match the recording too
+	for _, url := range []string{pbpURL, server.URL + "/games/1/pbp.json"} {
+		if status, body := send(t, client, http.MethodGet, url, ""); status != http.StatusOK || body != `{"id":"1","status":"closed"}` {
+			t.Errorf("replayed pbp = %d %s, want the recorded payload, repeated once all were served", status, body)
+		}
+	}
+	if unmatched := replayer.Unmatched(); len(unmatched) != 0 {
+		t.Errorf("Unmatched() = %v, want none", unmatched)
+	}
+}
+
+func TestReplayUnmatchedRequest(t *testing.T) {
+	server := newProviderServer(t)
+	dir := t.TempDir()
+	record(t, dir, server.URL)
+
+	replayer, err := LoadReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := retry.DefaultPolicy()
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/games/2/pbp.json?api_key=sr-key", nil)
+	_, err = policy.Do(&http.Client{Transport: replayer}, req)
+
+	var unmatchedErr *UnmatchedError
+	if !errors.As(err, &unmatchedErr) || !errors.Is(err, retry.ErrPermanent) {
+		t.Fatalf("replaying an unrecorded request returned %v, want a permanent UnmatchedError", err)
+	}
+	if strings.Contains(unmatchedErr.URL, "sr-key") {
+		t.Errorf("UnmatchedError url %s holds the api key", unmatchedErr.URL)
+	}
+	// The error is permanent, so the retry policy sent the request once
+	if unmatched := replayer.Unmatched(); len(unmatched) != 1 {
+		t.Errorf("Unmatched() = %v, want the request once", unmatched)
+	}
+}
+
+func TestLoadReplayerEmptyCassette(t *testing.T) {
+	if _, err := LoadReplayer(t.TempDir()); err == nil {
+		t.Error("LoadReplayer accepted an empty directory")
+	}
+}
@@ -0,0 +1,109 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper saving every request it sends and the response it gets to a
+// cassette directory, one file per interaction, secrets redacted. The responses are returned as
+// received. It is safe for concurrent use.
+type Recorder struct {
+	Base http.RoundTripper
+
+	m    sync.Mutex
+	dir  string
+	seqs map[string]int
+}
+
+// NewRecorder returns a Recorder sending the requests with base, http.DefaultTransport when nil,
+// and saving them to dir. The directory is created if needed and must not hold a cassette already.
+func NewRecorder(dir string, base http.RoundTripper) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating cassette directory: %w", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette directory %s: %w", dir, err)
+	}
+	if len(files) > 0 {
+		return nil, fmt.Errorf("cassette directory %s already holds %d interactions, record into an empty directory", dir, len(files))
+	}
+	return &Recorder{Base: base, dir: dir, seqs: make(map[string]int)}, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		// A RoundTripper must not modify the request it's given
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if err := r.save(req, reqBody, resp, respBody); err != nil {
+		return nil, fmt.Errorf("recording %s %s: %w", req.Method, redactURL(req.URL), err)
+	}
+	return resp, nil
+}
+
+// save writes an interaction to the cassette, secrets redacted
+func (r *Recorder) save(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) error {
+	header, body, err := decodeBody(resp.Header, respBody)
+	if err != nil {
+		return err
+	}
+
+	key := matchKey(req.Method, req.URL)
+	r.m.Lock()
+	r.seqs[key]++
+	seq := r.seqs[key]
+	r.m.Unlock()
+
+	interaction := Interaction{
+		Seq: seq,
+		Request: Request{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: redactHeader(req.Header),
+			Body:   string(redactJSON(reqBody, secretRequestFields)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(header),
+		},
+	}
+	setBody(&interaction.Response, redactJSON(body, secretResponseFields))
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling interaction: %w", err)
+	}
+	return os.WriteFile(filepath.Join(r.dir, fileName(key, req.URL, seq)), data, 0o644)
+}
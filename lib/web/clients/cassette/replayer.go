@@ -0,0 +1,107 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Replayer is an http.RoundTripper serving the responses of a cassette, without sending anything.
+// A request is served the responses recorded for its method and url in the order they were recorded,
+// the last one again once they were all served, so the retries of a run replay as they happened.
+// Requests without a recorded response fail with an UnmatchedError. It is safe for concurrent use.
+type Replayer struct {
+	m            sync.Mutex
+	interactions map[string][]*Interaction
+	served       map[string]int
+	unmatched    []string
+}
+
+// LoadReplayer reads the cassette recorded in dir
+func LoadReplayer(dir string) (*Replayer, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette directory %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no interactions recorded in %s", dir)
+	}
+
+	interactions := make(map[string][]*Interaction)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading interaction: %w", err)
+		}
+		interaction := &Interaction{}
+		if err := json.Unmarshal(data, interaction); err != nil {
+			return nil, fmt.Errorf("unmarshaling interaction %s: %w", file, err)
+		}
+		u, err := url.Parse(interaction.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url of interaction %s: %w", file, err)
+		}
+		key := matchKey(interaction.Request.Method, u)
+		interactions[key] = append(interactions[key], interaction)
+	}
+	for _, recorded := range interactions {
+		sort.Slice(recorded, func(i, j int) bool { return recorded[i].Seq < recorded[j].Seq })
+	}
+
+	return &Replayer{interactions: interactions, served: make(map[string]int)}, nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	key := matchKey(req.Method, req.URL)
+	r.m.Lock()
+	recorded := r.interactions[key]
+	if len(recorded) == 0 {
+		r.unmatched = append(r.unmatched, key)
+		r.m.Unlock()
+		return nil, &UnmatchedError{Method: req.Method, URL: redactURL(req.URL)}
+	}
+	index := min(r.served[key], len(recorded)-1)
+	r.served[key]++
+	r.m.Unlock()
+
+	recordedResp := recorded[index].Response
+	body, err := recordedResp.body()
+	if err != nil {
+		return nil, fmt.Errorf("decoding recorded body of %s: %w", key, err)
+	}
+	header := recordedResp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	// The body may be shorter than the one received, once redacted
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResp.StatusCode, http.StatusText(recordedResp.StatusCode)),
+		StatusCode:    recordedResp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Unmatched returns the requests that had no recorded response, as their method and url without secrets
+func (r *Replayer) Unmatched() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]string(nil), r.unmatched...)
+}
@@ -0,0 +1,73 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"gamedl/lib/web/clients/ratelimit"
+	"gamedl/lib/web/clients/usage"
+)
+
+// Options holds the HTTP settings shared by the provider clients
+type Options struct {
+	// Transport sends the requests, a transport using Proxy when nil
+	Transport http.RoundTripper
+	// Timeout limits the time of a request, reading its body included. No limit when 0.
+	Timeout time.Duration
+	// Proxy is the proxy of the default transport, the one of the environment (HTTPS_PROXY...) when nil.
+	// It's ignored when Transport is set.
+	Proxy *url.URL
+	// UserAgent replaces the User-Agent header of every request when set
+	UserAgent string
+	// RateLimits limits the requests per second sent to each endpoint class
+	RateLimits ratelimit.Rates
+	// Usage counts the requests, and refuses the ones it doesn't allow, when set
+	Usage usage.Recorder
+}
+
+// New returns an http client waiting on the rate limit of the requests, then counting the calls
+// right before they are sent with the transport of the options, their User-Agent set. Requests
+// given up while they wait on their rate limit aren't counted.
+func New(opts Options) *http.Client {
+	transport := opts.Transport
+	if transport == nil {
+		transport = DefaultTransport(opts.Proxy)
+	}
+	if opts.UserAgent != "" {
+		transport = &UserAgentTransport{Base: transport, UserAgent: opts.UserAgent}
+	}
+	if opts.Usage != nil {
+		transport = usage.NewTransport(transport, opts.Usage)
+	}
+	transport = ratelimit.NewTransport(transport, opts.RateLimits)
+	return &http.Client{Transport: transport, Timeout: opts.Timeout}
+}
+
+// DefaultTransport returns a copy of http.DefaultTransport sending the requests through proxy,
+// or through the proxy of the environment when nil
+func DefaultTransport(proxy *url.URL) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return transport
+}
+
+// UserAgentTransport is an http.RoundTripper setting the User-Agent header of the requests
+type UserAgentTransport struct {
+	Base      http.RoundTripper
+	UserAgent string
+}
+
+func (t *UserAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request it's given
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.UserAgent)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
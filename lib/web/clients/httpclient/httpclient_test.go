@@ -0,0 +1,58 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gamedl/lib/web/clients/ratelimit"
+	"gamedl/lib/web/clients/usage"
+)
+
+// countingRecorder counts the calls it's told about
+type countingRecorder struct {
+	m     sync.Mutex
+	calls int
+}
+
+func (r *countingRecorder) Use(usage.Call) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.calls++
+	return nil
+}
+
+func TestNewCountsCallsOnceRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	recorder := &countingRecorder{}
+	client := New(Options{RateLimits: ratelimit.Rates{ratelimit.Pbp: 1}, Usage: recorder})
+	send := func(ctx context.Context) error {
+		ctx = usage.WithCall(ratelimit.WithClass(ctx, ratelimit.Pbp), usage.Call{Endpoint: "pbp"})
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := send(context.Background()); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	// The next token comes in a second, so the request is given up while it waits on the limiter
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := send(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("rate limited request returned %v, want context.DeadlineExceeded", err)
+	}
+
+	if recorder.calls != 1 {
+		t.Errorf("recorder counted %d calls, want only the one sent", recorder.calls)
+	}
+}
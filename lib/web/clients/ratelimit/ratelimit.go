@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Class groups the endpoints of a provider that share a rate limit
+type Class string
+
+const (
+	// Schedule covers the season and schedule endpoints
+	Schedule Class = "schedule"
+	// Pbp covers the play by play endpoints
+	Pbp Class = "pbp"
+)
+
+// Rates maps endpoint classes to their allowed requests per second.
+// Classes missing from the map, or with a non-positive rate, aren't limited.
+type Rates map[Class]float64
+
+// Limiter is a token bucket that is safe for concurrent use
+type Limiter struct {
+	m      sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a limiter allowing perSecond requests per second with bursts of up to burst requests
+func NewLimiter(perSecond float64, burst int) *Limiter {
+	burst = max(burst, 1)
+	return &Limiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available and takes it, or until the context is done,
+// in which case the token it reserved is given back
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.m.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// Tokens can go negative: each waiting caller reserves its own slot in the future
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.m.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// release gives back the token reserved by a caller that stopped waiting,
+// so the callers coming after it don't wait for a slot nobody uses
+func (l *Limiter) release() {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.tokens = min(l.burst, l.tokens+1)
+}
+
+type classKey struct{}
+
+// WithClass returns a context tagging the requests made with it as belonging to an endpoint class
+func WithClass(ctx context.Context, class Class) context.Context {
+	return context.WithValue(ctx, classKey{}, class)
+}
+
+// ClassFromContext returns the endpoint class set with WithClass
+func ClassFromContext(ctx context.Context) (Class, bool) {
+	class, ok := ctx.Value(classKey{}).(Class)
+	return class, ok
+}
+
+// Transport is an http.RoundTripper that waits on the limiter of the request's
+// endpoint class before sending it. Requests without a class go through unlimited.
+type Transport struct {
+	Base     http.RoundTripper
+	Limiters map[Class]*Limiter
+}
+
+// NewTransport wraps base (http.DefaultTransport when nil) with one limiter per limited class
+func NewTransport(base http.RoundTripper, rates Rates) *Transport {
+	limiters := make(map[Class]*Limiter, len(rates))
+	for class, perSecond := range rates {
+		if perSecond > 0 {
+			limiters[class] = NewLimiter(perSecond, 1)
+		}
+	}
+	return &Transport{Base: base, Limiters: limiters}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if class, ok := ClassFromContext(req.Context()); ok {
+		if limiter := t.Limiters[class]; limiter != nil {
+			if err := limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
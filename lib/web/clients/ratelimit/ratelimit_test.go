@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterWait(t *testing.T) {
+	tests := []struct {
+		name      string
+		perSecond float64
+		burst     int
+		waits     int
+		min, max  time.Duration
+	}{
+		{"within the burst", 10, 3, 3, 0, 50 * time.Millisecond},
+		{"beyond the burst", 20, 1, 3, 90 * time.Millisecond, 250 * time.Millisecond},
+		{"burst below one", 20, 0, 2, 40 * time.Millisecond, 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(tt.perSecond, tt.burst)
+			start := time.Now()
+			for range tt.waits {
+				if err := limiter.Wait(context.Background()); err != nil {
+					t.Fatalf("Wait returned an error: %v", err)
+				}
+			}
+			if elapsed := time.Since(start); elapsed < tt.min || elapsed > tt.max {
+				t.Errorf("%d waits took %v, want between %v and %v", tt.waits, elapsed, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestLimiterWaitGivesBackCancelledReservations(t *testing.T) {
+	limiter := NewLimiter(10, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 5 {
+		if err := limiter.Wait(cancelled); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Wait with a cancelled context returned %v, want context.Canceled", err)
+		}
+	}
+
+	// Without the 5 cancelled reservations given back, the next token would come in 600ms
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("Wait after cancelled waits took %v, want about 100ms", elapsed)
+	}
+}
@@ -0,0 +1,237 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Policy describes how many times and how patiently a request is retried
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled on every following retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts, including the ones requested through Retry-After
+	MaxDelay time.Duration
+	// RetryableStatuses are the response status codes worth retrying
+	RetryableStatuses []int
+}
+
+// DefaultPolicy retries throttled and transient server errors up to 4 attempts
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// ErrPermanent marks the errors retrying can't fix, e.g. a request refused by the client itself.
+// Requests failing with an error wrapping it aren't retried.
+var ErrPermanent = errors.New("permanent error")
+
+// StatusError is returned when a request ended with a non 2xx status code
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+	Attempts   int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("bad status code: %d after %d attempt(s), body: %s", e.StatusCode, e.Attempts, string(e.Body))
+}
+
+// Stats records how the last request made with a context went, see WithStats
+type Stats struct {
+	// Attempts is the number of attempts the request took
+	Attempts int
+	// StatusCode is the status code of the last response, 0 when no response was received
+	StatusCode int
+}
+
+type statsKey struct{}
+
+// WithStats returns a context whose requests record their attempts and status code into stats.
+// stats must not be shared by requests running at the same time.
+func WithStats(ctx context.Context, stats *Stats) context.Context {
+	return context.WithValue(ctx, statsKey{}, stats)
+}
+
+// record updates the stats of the request's context, if any
+func record(req *http.Request, attempt int, resp *http.Response) {
+	stats, ok := req.Context().Value(statsKey{}).(*Stats)
+	if !ok {
+		return
+	}
+	stats.Attempts = attempt
+	stats.StatusCode = 0
+	if resp != nil {
+		stats.StatusCode = resp.StatusCode
+	}
+}
+
+// Do sends the request and reads its body, retrying network errors and retryable
+// status codes with exponential backoff and jitter. The request must be replayable,
+// i.e. have no body or a GetBody function.
+func (p Policy) Do(client *http.Client, req *http.Request) ([]byte, error) {
+	maxAttempts := max(p.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("could not rewind request body: %w", err)
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := client.Do(attemptReq)
+		record(req, attempt, resp)
+		if err != nil {
+			if attempt >= maxAttempts || errors.Is(err, ErrPermanent) {
+				return nil, fmt.Errorf("request failed after %d attempt(s): %w", attempt, err)
+			}
+			if err := sleep(req.Context(), p.backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			if attempt >= maxAttempts {
+				return nil, fmt.Errorf("could not read response body after %d attempt(s): %w", attempt, err)
+			}
+			if err := sleep(req.Context(), p.backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+			return body, nil
+		}
+
+		if attempt >= maxAttempts || !slices.Contains(p.RetryableStatuses, resp.StatusCode) {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Body: body, Attempts: attempt}
+		}
+
+		if err := sleep(req.Context(), p.retryDelay(attempt, resp)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Open sends the request like Do, but returns the body of the successful response unread so
+// large payloads can be streamed. Errors happening while the body is read aren't retried.
+// The caller must close the returned body.
+func (p Policy) Open(client *http.Client, req *http.Request) (io.ReadCloser, error) {
+	maxAttempts := max(p.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("could not rewind request body: %w", err)
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := client.Do(attemptReq)
+		record(req, attempt, resp)
+		if err != nil {
+			if attempt >= maxAttempts || errors.Is(err, ErrPermanent) {
+				return nil, fmt.Errorf("request failed after %d attempt(s): %w", attempt, err)
+			}
+			if err := sleep(req.Context(), p.backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+			return resp.Body, nil
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if attempt >= maxAttempts || !slices.Contains(p.RetryableStatuses, resp.StatusCode) {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Body: body, Attempts: attempt}
+		}
+
+		if err := sleep(req.Context(), p.retryDelay(attempt, resp)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for the given delay, or returns early with the context error when it's done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryDelay returns the delay before retrying a response the given attempt received: the one
+// of its Retry-After header if any, else the backoff one, capped by MaxDelay either way so a
+// provider asking to come back in hours doesn't stall the run
+func (p Policy) retryDelay(attempt int, resp *http.Response) time.Duration {
+	delay, ok := retryAfter(resp.Header.Get("Retry-After"))
+	if !ok {
+		return p.backoff(attempt)
+	}
+	if p.MaxDelay > 0 {
+		delay = min(delay, p.MaxDelay)
+	}
+	return delay
+}
+
+// backoff returns the delay before the retry following the given attempt,
+// picked randomly between half and the whole exponential delay
+func (p Policy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,189 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testPolicy retries without waiting, so the tests run fast
+func testPolicy(maxAttempts int) Policy {
+	policy := DefaultPolicy()
+	policy.MaxAttempts = maxAttempts
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = time.Millisecond
+	return policy
+}
+
+// statusServer answers with the given status codes in turn, the last one repeated, counting the requests
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		w.WriteHeader(status)
+		io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestDoRetriesRetryableStatuses(t *testing.T) {
+	server, requests := statusServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+
+	var stats Stats
+	req, _ := http.NewRequestWithContext(WithStats(context.Background(), &stats), http.MethodGet, server.URL, nil)
+	body, err := testPolicy(4).Do(server.Client(), req)
+	if err != nil {
+		t.Fatalf("Do returned an error: %v", err)
+	}
+	if string(body) != "OK" {
+		t.Errorf("body = %q, want %q", body, "OK")
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server got %d requests, want 3", got)
+	}
+	if stats.Attempts != 3 || stats.StatusCode != http.StatusOK {
+		t.Errorf("stats = %+v, want 3 attempts ending with 200", stats)
+	}
+}
+
+func TestDoReturnsStatusErrorAfterMaxAttempts(t *testing.T) {
+	server, requests := statusServer(t, http.StatusServiceUnavailable)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := testPolicy(3).Do(server.Client(), req)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("Do returned %v, want a *StatusError", err)
+	}
+	if statusErr.StatusCode != http.StatusServiceUnavailable || statusErr.Attempts != 3 {
+		t.Errorf("StatusError = %d after %d attempts, want 503 after 3", statusErr.StatusCode, statusErr.Attempts)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server got %d requests, want 3", got)
+	}
+}
+
+func TestDoDoesNotRetryOtherStatuses(t *testing.T) {
+	server, requests := statusServer(t, http.StatusNotFound, http.StatusOK)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := testPolicy(4).Do(server.Client(), req)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound || statusErr.Attempts != 1 {
+		t.Fatalf("Do returned %v, want a 404 StatusError after 1 attempt", err)
+	}
+	if string(statusErr.Body) != "Not Found" {
+		t.Errorf("StatusError body = %q, want %q", statusErr.Body, "Not Found")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+// failingTransport fails every request with err, counting them
+type failingTransport struct {
+	err      error
+	requests int
+}
+
+func (f *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	f.requests++
+	return nil, f.err
+}
+
+func TestDoDoesNotRetryPermanentErrors(t *testing.T) {
+	transport := &failingTransport{err: ErrPermanent}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid/", nil)
+	_, err := testPolicy(4).Do(&http.Client{Transport: transport}, req)
+	if !errors.Is(err, ErrPermanent) {
+		t.Fatalf("Do returned %v, want an error wrapping ErrPermanent", err)
+	}
+	if transport.requests != 1 {
+		t.Errorf("transport got %d requests, want 1", transport.requests)
+	}
+}
+
+func TestDoRetriesNetworkErrors(t *testing.T) {
+	transport := &failingTransport{err: errors.New("connection reset")}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid/", nil)
+	if _, err := testPolicy(3).Do(&http.Client{Transport: transport}, req); err == nil {
+		t.Fatal("Do returned no error")
+	}
+	if transport.requests != 3 {
+		t.Errorf("transport got %d requests, want 3", transport.requests)
+	}
+}
+
+func TestOpenStreamsSuccessfulBody(t *testing.T) {
+	server, requests := statusServer(t, http.StatusBadGateway, http.StatusOK)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	body, err := testPolicy(4).Open(server.Client(), req)
+	if err != nil {
+		t.Fatalf("Open returned an error: %v", err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil || string(data) != "OK" {
+		t.Errorf("body = %q, %v, want %q", data, err, "OK")
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRetryDelayCapsRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		maxDelay   time.Duration
+		want       time.Duration
+	}{
+		{"short delay", "2", 30 * time.Second, 2 * time.Second},
+		{"delay longer than the max one", "3600", 30 * time.Second, 30 * time.Second},
+		{"date far away", time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat), 30 * time.Second, 30 * time.Second},
+		{"no max delay", "3600", 0, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := DefaultPolicy()
+			policy.MaxDelay = tt.maxDelay
+			resp := &http.Response{Header: http.Header{"Retry-After": []string{tt.retryAfter}}}
+			if got := policy.retryDelay(1, resp); got != tt.want {
+				t.Errorf("retryDelay with Retry-After %q = %v, want %v", tt.retryAfter, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package sportsradar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gamedl/lib/web/clients/retry"
+)
+
+// AccessLevel is the access level of the SportRadar api keys, which is part of the request urls
+type AccessLevel string
+
+const (
+	Trial      AccessLevel = "trial"
+	Production AccessLevel = "production"
+)
+
+// AccessLevels are the access levels a client supports
+var AccessLevels = []AccessLevel{Trial, Production}
+
+// APIConfig selects the SportRadar API of a competition. Empty fields keep their default.
+type APIConfig struct {
+	AccessLevel AccessLevel
+	// Version is the version of the API, e.g. v8
+	Version string
+	// Locale is the language of the descriptions of the payloads, e.g. en
+	Locale string
+}
+
+// apiHost is the default host of every SportRadar API, see WithBaseURL
+const apiHost = "https://api.sportradar.com"
+
+// competitionAPI is the SportRadar API of a competition
+type competitionAPI struct {
+	competition string
+	host        string
+	// product is the path of the API on the host, e.g. nfl/official
+	product  string
+	versions []string
+	config   APIConfig
+	keys     *keyRing
+}
+
+// competitionAPIs returns the APIs of the competitions with their default configuration.
+// The first version of each API is its default one.
+func competitionAPIs() map[string]*competitionAPI {
+	apis := map[string]*competitionAPI{
+		"nba":   {product: "nba", versions: []string{"v8", "v7"}},
+		"ncaab": {product: "ncaamb", versions: []string{"v8", "v7"}},
+		"ncaaf": {product: "ncaafb", versions: []string{"v7"}},
+		"nfl":   {product: "nfl/official", versions: []string{"v7"}},
+	}
+	for competition, api := range apis {
+		api.competition = competition
+		api.host = apiHost
+		api.config = APIConfig{AccessLevel: Trial, Version: api.versions[0], Locale: "en"}
+		api.keys = newKeyRing(competition)
+	}
+	return apis
+}
+
+// Competitions are the competitions of the SportRadar APIs a client supports
+var Competitions = []string{"nba", "ncaab", "ncaaf", "nfl"}
+
+// set overrides the configuration of the API with the fields of config that aren't empty
+func (a *competitionAPI) set(config APIConfig) {
+	if config.AccessLevel != "" {
+		a.config.AccessLevel = config.AccessLevel
+	}
+	if config.Version != "" {
+		a.config.Version = config.Version
+	}
+	if config.Locale != "" {
+		a.config.Locale = config.Locale
+	}
+}
+
+// baseURL returns the url the paths of the API requests are relative to, e.g. https://api.sportradar.com/nba/trial/v8/en
+func (a *competitionAPI) baseURL() string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", a.host, a.product, a.config.AccessLevel, a.config.Version, a.config.Locale)
+}
+
+var localePattern = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+
+// ValidateAPIConfig checks the fields of config that aren't empty against the API of a competition
+func ValidateAPIConfig(competition string, config APIConfig) error {
+	api, ok := competitionAPIs()[competition]
+	if !ok {
+		return fmt.Errorf("unknown SportRadar competition %s", competition)
+	}
+	if config.AccessLevel != "" && !slices.Contains(AccessLevels, config.AccessLevel) {
+		return fmt.Errorf("invalid SportRadar access level %s, allowed values: %s", config.AccessLevel, joinAccessLevels())
+	}
+	if config.Version != "" && !slices.Contains(api.versions, config.Version) {
+		return fmt.Errorf("SportRadar %s API has no version %s, allowed values: %s", competition, config.Version, strings.Join(api.versions, ", "))
+	}
+	if config.Locale != "" && !localePattern.MatchString(config.Locale) {
+		return fmt.Errorf("invalid SportRadar locale %s, expected a language code such as en or es", config.Locale)
+	}
+	return nil
+}
+
+func joinAccessLevels() string {
+	levels := make([]string, 0, len(AccessLevels))
+	for _, level := range AccessLevels {
+		levels = append(levels, string(level))
+	}
+	return strings.Join(levels, ", ")
+}
+
+// AccessError is returned when SportRadar refuses a key with a 401 or 403 status code, which it
+// does for keys used at an access level they weren't issued for (as well as for exhausted quotas)
+type AccessError struct {
+	Competition string
+	AccessLevel AccessLevel
+	KeyID       string
+	Err         *retry.StatusError
+}
+
+func (e *AccessError) Error() string {
+	return fmt.Sprintf("SportRadar %s key %s refused with status code %d at the %s access level, check the key was issued for this access level and has quota left: %v",
+		e.Competition, e.KeyID, e.Err.StatusCode, e.AccessLevel, e.Err)
+}
+
+func (e *AccessError) Unwrap() error {
+	return e.Err
+}
+
+// accessError wraps err in an AccessError when the key of the request was refused
+func (a *competitionAPI) accessError(key *apiKey, err error) error {
+	var statusErr *retry.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+	if statusErr.StatusCode != http.StatusUnauthorized && statusErr.StatusCode != http.StatusForbidden {
+		return err
+	}
+	return &AccessError{Competition: a.competition, AccessLevel: a.config.AccessLevel, KeyID: key.KeyID, Err: statusErr}
+}
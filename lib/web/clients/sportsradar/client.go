@@ -1,53 +1,266 @@
 package sportsradar
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gamedl/lib/web/clients/httpclient"
+	"gamedl/lib/web/clients/ratelimit"
+	"gamedl/lib/web/clients/retry"
+	"gamedl/lib/web/clients/usage"
 )
 
 type ClientOption func(*Client)
 
 func WithNcaafAPIKey(apiKey string) ClientOption {
 	return func(client *Client) {
-		client.ncaafAPIKey = apiKey
+		client.ncaaf.keys.add(apiKey)
+	}
+}
+
+// WithNcaafAPIConfig selects the access level, version and locale of the NCAAF API, see ValidateAPIConfig
+func WithNcaafAPIConfig(config APIConfig) ClientOption {
+	return func(client *Client) {
+		client.ncaaf.set(config)
+	}
+}
+
+// WithNcaafAPIKeys adds api keys the NCAAF requests rotate between, see WithKeyRotation
+func WithNcaafAPIKeys(apiKeys ...string) ClientOption {
+	return func(client *Client) {
+		client.ncaaf.keys.add(apiKeys...)
+	}
+}
+
+func WithNflAPIKey(apiKey string) ClientOption {
+	return func(client *Client) {
+		client.nfl.keys.add(apiKey)
+	}
+}
+
+// WithNflAPIConfig selects the access level, version and locale of the NFL API, see ValidateAPIConfig
+func WithNflAPIConfig(config APIConfig) ClientOption {
+	return func(client *Client) {
+		client.nfl.set(config)
+	}
+}
+
+// WithNflAPIKeys adds api keys the NFL requests rotate between, see WithKeyRotation
+func WithNflAPIKeys(apiKeys ...string) ClientOption {
+	return func(client *Client) {
+		client.nfl.keys.add(apiKeys...)
 	}
 }
 
 func WithNcaabAPIKey(apiKey string) ClientOption {
 	return func(client *Client) {
-		client.ncaabAPIKey = apiKey
+		client.ncaab.keys.add(apiKey)
+	}
+}
+
+// WithNcaabAPIConfig selects the access level, version and locale of the NCAAB API, see ValidateAPIConfig
+func WithNcaabAPIConfig(config APIConfig) ClientOption {
+	return func(client *Client) {
+		client.ncaab.set(config)
+	}
+}
+
+// WithNcaabAPIKeys adds api keys the NCAAB requests rotate between, see WithKeyRotation
+func WithNcaabAPIKeys(apiKeys ...string) ClientOption {
+	return func(client *Client) {
+		client.ncaab.keys.add(apiKeys...)
 	}
 }
 
 func WithNbaAPIKey(apiKey string) ClientOption {
 	return func(client *Client) {
-		client.nbaAPIKey = apiKey
+		client.nba.keys.add(apiKey)
 	}
 }
 
-type Client struct {
-	client *http.Client
+// WithNbaAPIConfig selects the access level, version and locale of the NBA API, see ValidateAPIConfig
+func WithNbaAPIConfig(config APIConfig) ClientOption {
+	return func(client *Client) {
+		client.nba.set(config)
+	}
+}
 
-	ncaafAPIKey  string
-	ncaafBaseURL string
+// WithNbaAPIKeys adds api keys the NBA requests rotate between, see WithKeyRotation
+func WithNbaAPIKeys(apiKeys ...string) ClientOption {
+	return func(client *Client) {
+		client.nba.keys.add(apiKeys...)
+	}
+}
 
-	ncaabAPIKey  string
-	ncaabBaseURL string
+// WithRateLimits limits the requests per second sent to each endpoint class.
+// The limits are shared by every goroutine using the client.
+func WithRateLimits(rates ratelimit.Rates) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.RateLimits = rates
+	}
+}
 
-	nbaAPIKey  string
-	nbaBaseURL string
+// WithUsage counts the requests sent with each api key, and refuses the ones the recorder doesn't allow
+func WithUsage(recorder usage.Recorder) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.Usage = recorder
+	}
+}
+
+// WithBaseURL sends the requests to another host than https://api.sportradar.com, e.g. a local fake server.
+// The paths of the requests are unchanged.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(client *Client) {
+		for _, api := range client.apis() {
+			api.host = strings.TrimSuffix(baseURL, "/")
+		}
+	}
+}
+
+// WithTransport sends the requests with transport instead of a default one
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.Transport = transport
+	}
+}
+
+// WithTimeout limits the time of each attempt of a request, reading its body included
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.Timeout = timeout
+	}
+}
+
+// WithProxy sends the requests through a proxy instead of the one of the environment.
+// It has no effect with WithTransport.
+func WithProxy(proxy *url.URL) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.Proxy = proxy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests
+func WithUserAgent(userAgent string) ClientOption {
+	return func(client *Client) {
+		client.httpOptions.UserAgent = userAgent
+	}
+}
+
+// WithKeyRotation sets how the key of a request is picked when a competition has several api keys
+// (default: RoundRobin). Whatever the rotation, a key refused for its quota or access level (403 status code,
+// or monthly budget of the usage recorder) is marked exhausted and the request is sent again with the next key.
+// A 429 status code only throttles the requests per second, so it is retried by the retry policy instead.
+func WithKeyRotation(rotation Rotation) ClientOption {
+	return func(client *Client) {
+		client.rotation = rotation
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried
+func WithRetryPolicy(policy retry.Policy) ClientOption {
+	return func(client *Client) {
+		client.retryPolicy = policy
+	}
+}
+
+type Client struct {
+	client      *http.Client
+	httpOptions httpclient.Options
+	retryPolicy retry.Policy
+	rotation    Rotation
+
+	nfl   *competitionAPI
+	ncaaf *competitionAPI
+	ncaab *competitionAPI
+	nba   *competitionAPI
 }
 
 func NewClient(options ...ClientOption) *Client {
+	apis := competitionAPIs()
 	client := &Client{
-		client:       &http.Client{},
-		ncaafBaseURL: "https://api.sportradar.com/ncaafb/trial/v7",
-		ncaabBaseURL: "https://api.sportradar.com/ncaamb/trial/v8",
-		nbaBaseURL:   "https://api.sportradar.com/nba/trial/v8",
+		retryPolicy: retry.DefaultPolicy(),
+		rotation:    RoundRobin,
+		nfl:         apis["nfl"],
+		ncaaf:       apis["ncaaf"],
+		ncaab:       apis["ncaab"],
+		nba:         apis["nba"],
 	}
 
 	for _, option := range options {
 		option(client)
 	}
+	client.client = httpclient.New(client.httpOptions)
 
 	return client
 }
+
+// apis returns the APIs of the competitions
+func (c *Client) apis() []*competitionAPI {
+	return []*competitionAPI{c.nba, c.ncaab, c.ncaaf, c.nfl}
+}
+
+// KeyStats returns the usage of the api keys of every competition
+func (c *Client) KeyStats() []KeyStats {
+	var stats []KeyStats
+	for _, api := range c.apis() {
+		stats = append(stats, api.keys.stats()...)
+	}
+	return stats
+}
+
+// get fetches the url of an endpoint of a competition with one of its api keys, following the
+// client's retry policy and rate limits, and returns the body of a successful reply
+func (c *Client) get(ctx context.Context, api *competitionAPI, endpoint string, class ratelimit.Class, url string) ([]byte, error) {
+	var body []byte
+	err := c.withKey(ctx, api, endpoint, class, url, func(req *http.Request) error {
+		var err error
+		body, err = c.retryPolicy.Do(c.client, req)
+		return err
+	})
+	return body, err
+}
+
+// open fetches the url like get, but returns the body of a successful reply unread so it can be streamed
+func (c *Client) open(ctx context.Context, api *competitionAPI, endpoint string, class ratelimit.Class, url string) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := c.withKey(ctx, api, endpoint, class, url, func(req *http.Request) error {
+		var err error
+		body, err = c.retryPolicy.Open(c.client, req)
+		return err
+	})
+	return body, err
+}
+
+// withKey sends the request built for the url with a key of the API, and sends it again with
+// the next key as long as the key used is exhausted
+func (c *Client) withKey(ctx context.Context, api *competitionAPI, endpoint string, class ratelimit.Class, url string, send func(req *http.Request) error) error {
+	var refusal error
+	for {
+		key, err := api.keys.pick(c.rotation)
+		if err != nil && refusal != nil {
+			return fmt.Errorf("%w, the last one was refused with: %w", err, refusal)
+		}
+		if err != nil {
+			return err
+		}
+		call := usage.Call{Credential: "sportradar/" + api.competition, KeyID: usage.KeyID(key.key), Endpoint: endpoint}
+		req, err := http.NewRequestWithContext(usage.WithCall(ratelimit.WithClass(ctx, class), call), http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("could not create request: %w", err)
+		}
+		// The key is sent as a header rather than in the url, so it doesn't end up in errors, which quote the url
+		if key.key != "" {
+			req.Header.Set("X-Api-Key", key.key)
+		}
+		err = api.accessError(key, send(req))
+		if err == nil || !api.keys.exhaust(key, err) {
+			return err
+		}
+		refusal = err
+	}
+}
@@ -1,30 +1,26 @@
 package sportsradar
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+
+	"gamedl/lib/web/clients/ratelimit"
 )
 
-func (c *Client) GetNbaSeasonsRaw() ([]byte, error) {
-	url := fmt.Sprintf("%s/en/league/seasons.json?api_key=%s", c.nbaBaseURL, c.nbaAPIKey)
-	resp, err := c.client.Get(url)
+func (c *Client) GetNbaSeasonsRaw(ctx context.Context) ([]byte, error) {
+	url := fmt.Sprintf("%s/league/seasons.json", c.nba.baseURL())
+	body, err := c.get(ctx, c.nba, "seasons", ratelimit.Schedule, url)
 	if err != nil {
 		return nil, fmt.Errorf("could not get seasons: %w", err)
 	}
 
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("could not read body of seasons reply: %w", err)
-	}
-
 	return body, nil
 }
 
-func (c *Client) GetNbaSeasons() (*NBASeasonsInfo, error) {
-	body, err := c.GetNbaSeasonsRaw()
+func (c *Client) GetNbaSeasons(ctx context.Context) (*NBASeasonsInfo, error) {
+	body, err := c.GetNbaSeasonsRaw(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -35,22 +31,17 @@ func (c *Client) GetNbaSeasons() (*NBASeasonsInfo, error) {
 	return seasons, nil
 }
 
-func (c *Client) GetNbaSeasonScheduleRaw(year int) ([]byte, error) {
-	url := fmt.Sprintf("%s/en/games/%d/REG/schedule.json?api_key=%s", c.nbaBaseURL, year, c.nbaAPIKey)
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("could not get season schedule for year %d: %w", year, err)
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+func (c *Client) GetNbaSeasonScheduleRaw(ctx context.Context, year int, seasonType string) ([]byte, error) {
+	url := fmt.Sprintf("%s/games/%d/%s/schedule.json", c.nba.baseURL(), year, seasonType)
+	body, err := c.get(ctx, c.nba, "schedule", ratelimit.Schedule, url)
 	if err != nil {
-		return nil, fmt.Errorf("could not read body of season schedule reply: %w", err)
+		return nil, fmt.Errorf("could not get %s season schedule for year %d: %w", seasonType, year, err)
 	}
 	return body, nil
 }
 
-func (c *Client) GetNbaSeasonSchedule(year int) (*NbaSeasonSchedule, error) {
-	body, err := c.GetNbaSeasonScheduleRaw(year)
+func (c *Client) GetNbaSeasonSchedule(ctx context.Context, year int, seasonType string) (*NbaSeasonSchedule, error) {
+	body, err := c.GetNbaSeasonScheduleRaw(ctx, year, seasonType)
 	if err != nil {
 		return nil, err
 	}
@@ -61,22 +52,32 @@ func (c *Client) GetNbaSeasonSchedule(year int) (*NbaSeasonSchedule, error) {
 	return schedule, nil
 }
 
-func (c *Client) GetNbaPbpOfGameRaw(gameId string) ([]byte, error) {
-	url := fmt.Sprintf("%s/en/games/%s/pbp.json?api_key=%s", c.nbaBaseURL, gameId, c.nbaAPIKey)
-	resp, err := c.client.Get(url)
+// NbaPbpOfGameURL returns the url of the play by play of a game, without the api key
+func (c *Client) NbaPbpOfGameURL(gameId string) string {
+	return fmt.Sprintf("%s/games/%s/pbp.json", c.nba.baseURL(), gameId)
+}
+
+func (c *Client) GetNbaPbpOfGameRaw(ctx context.Context, gameId string) ([]byte, error) {
+	url := c.NbaPbpOfGameURL(gameId)
+	body, err := c.get(ctx, c.nba, "pbp", ratelimit.Pbp, url)
 	if err != nil {
 		return nil, fmt.Errorf("could not get play by play of game %s: %w", gameId, err)
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	return body, nil
+}
+
+// OpenNbaPbpOfGame streams the play by play of a game. The caller must close the returned body.
+func (c *Client) OpenNbaPbpOfGame(ctx context.Context, gameId string) (io.ReadCloser, error) {
+	url := c.NbaPbpOfGameURL(gameId)
+	body, err := c.open(ctx, c.nba, "pbp", ratelimit.Pbp, url)
 	if err != nil {
-		return nil, fmt.Errorf("could not read body of play by play reply: %w", err)
+		return nil, fmt.Errorf("could not get play by play of game %s: %w", gameId, err)
 	}
 	return body, nil
 }
 
-func (c *Client) GetNbaPbpOfGame(gameId string) (*NbaGamePbp, error) {
-	body, err := c.GetNbaPbpOfGameRaw(gameId)
+func (c *Client) GetNbaPbpOfGame(ctx context.Context, gameId string) (*NbaGamePbp, error) {
+	body, err := c.GetNbaPbpOfGameRaw(ctx, gameId)
 	if err != nil {
 		return nil, err
 	}
@@ -1,30 +1,26 @@
 package sportsradar
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+
+	"gamedl/lib/web/clients/ratelimit"
 )
 
-func (c *Client) GetNcaabSeasonsRaw() ([]byte, error) {
-	url := fmt.Sprintf("%s/en/league/seasons.json?api_key=%s", c.ncaabBaseURL, c.ncaabAPIKey)
-	resp, err := c.client.Get(url)
+func (c *Client) GetNcaabSeasonsRaw(ctx context.Context) ([]byte, error) {
+	url := fmt.Sprintf("%s/league/seasons.json", c.ncaab.baseURL())
+	body, err := c.get(ctx, c.ncaab, "seasons", ratelimit.Schedule, url)
 	if err != nil {
 		return nil, fmt.Errorf("could not get seasons: %w", err)
 	}
 
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("could not read body of seasons reply: %w", err)
-	}
-
 	return body, nil
 }
 
-func (c *Client) GetNcaabSeasons() (*NcaabSeasonsInfo, error) {
-	body, err := c.GetNcaabSeasonsRaw()
+func (c *Client) GetNcaabSeasons(ctx context.Context) (*NcaabSeasonsInfo, error) {
+	body, err := c.GetNcaabSeasonsRaw(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -35,22 +31,17 @@ func (c *Client) GetNcaabSeasons() (*NcaabSeasonsInfo, error) {
 	return seasons, nil
 }
 
-func (c *Client) GetNcaabSeasonScheduleRaw(year int) ([]byte, error) {
-	url := fmt.Sprintf("%s/en/games/%d/REG/schedule.json?api_key=%s", c.ncaabBaseURL, year, c.ncaabAPIKey)
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("could not get season schedule for year %d: %w", year, err)
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+func (c *Client) GetNcaabSeasonScheduleRaw(ctx context.Context, year int, seasonType string) ([]byte, error) {
+	url := fmt.Sprintf("%s/games/%d/%s/schedule.json", c.ncaab.baseURL(), year, seasonType)
+	body, err := c.get(ctx, c.ncaab, "schedule", ratelimit.Schedule, url)
 	if err != nil {
-		return nil, fmt.Errorf("could not read body of season schedule reply: %w", err)
+		return nil, fmt.Errorf("could not get %s season schedule for year %d: %w", seasonType, year, err)
 	}
 	return body, nil
 }
 
-func (c *Client) GetNcaabSeasonSchedule(year int) (*NcaabSeasonSchedule, error) {
-	body, err := c.GetNcaabSeasonScheduleRaw(year)
+func (c *Client) GetNcaabSeasonSchedule(ctx context.Context, year int, seasonType string) (*NcaabSeasonSchedule, error) {
+	body, err := c.GetNcaabSeasonScheduleRaw(ctx, year, seasonType)
 	if err != nil {
 		return nil, err
 	}
@@ -61,22 +52,32 @@ func (c *Client) GetNcaabSeasonSchedule(year int) (*NcaabSeasonSchedule, error)
 	return schedule, nil
 }
 
-func (c *Client) GetNcaabPbpOfGameRaw(gameId string) ([]byte, error) {
-	url := fmt.Sprintf("%s/en/games/%s/pbp.json?api_key=%s", c.ncaabBaseURL, gameId, c.ncaabAPIKey)
-	resp, err := c.client.Get(url)
+// NcaabPbpOfGameURL returns the url of the play by play of a game, without the api key
+func (c *Client) NcaabPbpOfGameURL(gameId string) string {
+	return fmt.Sprintf("%s/games/%s/pbp.json", c.ncaab.baseURL(), gameId)
+}
+
+func (c *Client) GetNcaabPbpOfGameRaw(ctx context.Context, gameId string) ([]byte, error) {
+	url := c.NcaabPbpOfGameURL(gameId)
+	body, err := c.get(ctx, c.ncaab, "pbp", ratelimit.Pbp, url)
 	if err != nil {
 		return nil, fmt.Errorf("could not get play by play of game %s: %w", gameId, err)
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	return body, nil
+}
+
+// OpenNcaabPbpOfGame streams the play by play of a game. The caller must close the returned body.
+func (c *Client) OpenNcaabPbpOfGame(ctx context.Context, gameId string) (io.ReadCloser, error) {
+	url := c.NcaabPbpOfGameURL(gameId)
+	body, err := c.open(ctx, c.ncaab, "pbp", ratelimit.Pbp, url)
 	if err != nil {
-		return nil, fmt.Errorf("could not read body of play by play reply: %w", err)
+		return nil, fmt.Errorf("could not get play by play of game %s: %w", gameId, err)
 	}
 	return body, nil
 }
 
-func (c *Client) GetNcaabPbpOfGame(gameId string) (*NcaabGamePbp, error) {
-	body, err := c.GetNcaabPbpOfGameRaw(gameId)
+func (c *Client) GetNcaabPbpOfGame(ctx context.Context, gameId string) (*NcaabGamePbp, error) {
+	body, err := c.GetNcaabPbpOfGameRaw(ctx, gameId)
 	if err != nil {
 		return nil, err
 	}
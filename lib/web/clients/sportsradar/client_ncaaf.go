@@ -1,30 +1,26 @@
 package sportsradar
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+
+	"gamedl/lib/web/clients/ratelimit"
 )
 
-func (c *Client) GetNcaafSeasonsRaw() ([]byte, error) {
-	url := fmt.Sprintf("%s/en/league/seasons.json?api_key=%s", c.ncaafBaseURL, c.ncaafAPIKey)
-	resp, err := c.client.Get(url)
+func (c *Client) GetNcaafSeasonsRaw(ctx context.Context) ([]byte, error) {
+	url := fmt.Sprintf("%s/league/seasons.json", c.ncaaf.baseURL())
+	body, err := c.get(ctx, c.ncaaf, "seasons", ratelimit.Schedule, url)
 	if err != nil {
 		return nil, fmt.Errorf("could not get seasons: %w", err)
 	}
 
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("could not read body of seasons reply: %w", err)
-	}
-
 	return body, nil
 }
 
-func (c *Client) GetNcaafSeasons() (*NcaafSeasonsInfo, error) {
-	body, err := c.GetNcaafSeasonsRaw()
+func (c *Client) GetNcaafSeasons(ctx context.Context) (*NcaafSeasonsInfo, error) {
+	body, err := c.GetNcaafSeasonsRaw(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -35,22 +31,17 @@ func (c *Client) GetNcaafSeasons() (*NcaafSeasonsInfo, error) {
 	return seasons, nil
 }
 
-func (c *Client) GetNcaafSeasonScheduleRaw(year int) ([]byte, error) {
-	url := fmt.Sprintf("%s/en/games/%d/REG/schedule.json?api_key=%s", c.ncaafBaseURL, year, c.ncaafAPIKey)
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("could not get season schedule for year %d: %w", year, err)
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+func (c *Client) GetNcaafSeasonScheduleRaw(ctx context.Context, year int, seasonType string) ([]byte, error) {
+	url := fmt.Sprintf("%s/games/%d/%s/schedule.json", c.ncaaf.baseURL(), year, seasonType)
+	body, err := c.get(ctx, c.ncaaf, "schedule", ratelimit.Schedule, url)
 	if err != nil {
-		return nil, fmt.Errorf("could not read body of season schedule reply: %w", err)
+		return nil, fmt.Errorf("could not get %s season schedule for year %d: %w", seasonType, year, err)
 	}
 	return body, nil
 }
 
-func (c *Client) GetNcaafSeasonSchedule(year int) (*NcaafSeasonSchedule, error) {
-	body, err := c.GetNcaafSeasonScheduleRaw(year)
+func (c *Client) GetNcaafSeasonSchedule(ctx context.Context, year int, seasonType string) (*NcaafSeasonSchedule, error) {
+	body, err := c.GetNcaafSeasonScheduleRaw(ctx, year, seasonType)
 	if err != nil {
 		return nil, err
 	}
@@ -61,22 +52,32 @@ func (c *Client) GetNcaafSeasonSchedule(year int) (*NcaafSeasonSchedule, error)
 	return schedule, nil
 }
 
-func (c *Client) GetNcaafPbpOfGameRaw(gameId string) ([]byte, error) {
-	url := fmt.Sprintf("%s/en/games/%s/pbp.json?api_key=%s", c.ncaafBaseURL, gameId, c.ncaafAPIKey)
-	resp, err := c.client.Get(url)
+// NcaafPbpOfGameURL returns the url of the play by play of a game, without the api key
+func (c *Client) NcaafPbpOfGameURL(gameId string) string {
+	return fmt.Sprintf("%s/games/%s/pbp.json", c.ncaaf.baseURL(), gameId)
+}
+
+func (c *Client) GetNcaafPbpOfGameRaw(ctx context.Context, gameId string) ([]byte, error) {
+	url := c.NcaafPbpOfGameURL(gameId)
+	body, err := c.get(ctx, c.ncaaf, "pbp", ratelimit.Pbp, url)
 	if err != nil {
 		return nil, fmt.Errorf("could not get play by play of game %s: %w", gameId, err)
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	return body, nil
+}
+
+// OpenNcaafPbpOfGame streams the play by play of a game. The caller must close the returned body.
+func (c *Client) OpenNcaafPbpOfGame(ctx context.Context, gameId string) (io.ReadCloser, error) {
+	url := c.NcaafPbpOfGameURL(gameId)
+	body, err := c.open(ctx, c.ncaaf, "pbp", ratelimit.Pbp, url)
 	if err != nil {
-		return nil, fmt.Errorf("could not read body of play by play reply: %w", err)
+		return nil, fmt.Errorf("could not get play by play of game %s: %w", gameId, err)
 	}
 	return body, nil
 }
 
-func (c *Client) GetNcaafPbpOfGame(gameId string) (*NcaafGamePbp, error) {
-	body, err := c.GetNcaafPbpOfGameRaw(gameId)
+func (c *Client) GetNcaafPbpOfGame(ctx context.Context, gameId string) (*NcaafGamePbp, error) {
+	body, err := c.GetNcaafPbpOfGameRaw(ctx, gameId)
 	if err != nil {
 		return nil, err
 	}
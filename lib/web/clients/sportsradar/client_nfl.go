@@ -0,0 +1,111 @@
+package sportsradar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gamedl/lib/web/clients/ratelimit"
+)
+
+func (c *Client) GetNflSeasonsRaw(ctx context.Context) ([]byte, error) {
+	url := fmt.Sprintf("%s/league/seasons.json", c.nfl.baseURL())
+	body, err := c.get(ctx, c.nfl, "seasons", ratelimit.Schedule, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not get seasons: %w", err)
+	}
+
+	return body, nil
+}
+
+func (c *Client) GetNflSeasons(ctx context.Context) (*NflSeasonsInfo, error) {
+	body, err := c.GetNflSeasonsRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seasons := &NflSeasonsInfo{}
+	if err := json.Unmarshal(body, seasons); err != nil {
+		return nil, fmt.Errorf("could not unmarshal seasons reply: %w", err)
+	}
+	return seasons, nil
+}
+
+func (c *Client) GetNflSeasonScheduleRaw(ctx context.Context, year int, seasonType string) ([]byte, error) {
+	url := fmt.Sprintf("%s/games/%d/%s/schedule.json", c.nfl.baseURL(), year, seasonType)
+	body, err := c.get(ctx, c.nfl, "schedule", ratelimit.Schedule, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not get %s season schedule for year %d: %w", seasonType, year, err)
+	}
+	return body, nil
+}
+
+func (c *Client) GetNflSeasonSchedule(ctx context.Context, year int, seasonType string) (*NflSeasonSchedule, error) {
+	body, err := c.GetNflSeasonScheduleRaw(ctx, year, seasonType)
+	if err != nil {
+		return nil, err
+	}
+	schedule := &NflSeasonSchedule{}
+	if err := json.Unmarshal(body, schedule); err != nil {
+		return nil, fmt.Errorf("could not unmarshal season schedule reply: %w", err)
+	}
+	return schedule, nil
+}
+
+// NflPbpOfGameURL returns the url of the play by play of a game, without the api key
+func (c *Client) NflPbpOfGameURL(gameId string) string {
+	return fmt.Sprintf("%s/games/%s/pbp.json", c.nfl.baseURL(), gameId)
+}
+
+func (c *Client) GetNflPbpOfGameRaw(ctx context.Context, gameId string) ([]byte, error) {
+	url := c.NflPbpOfGameURL(gameId)
+	body, err := c.get(ctx, c.nfl, "pbp", ratelimit.Pbp, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not get play by play of game %s: %w", gameId, err)
+	}
+	return body, nil
+}
+
+// OpenNflPbpOfGame streams the play by play of a game. The caller must close the returned body.
+func (c *Client) OpenNflPbpOfGame(ctx context.Context, gameId string) (io.ReadCloser, error) {
+	url := c.NflPbpOfGameURL(gameId)
+	body, err := c.open(ctx, c.nfl, "pbp", ratelimit.Pbp, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not get play by play of game %s: %w", gameId, err)
+	}
+	return body, nil
+}
+
+func (c *Client) GetNflPbpOfGame(ctx context.Context, gameId string) (*NflGamePbp, error) {
+	body, err := c.GetNflPbpOfGameRaw(ctx, gameId)
+	if err != nil {
+		return nil, err
+	}
+	pbp := &NflGamePbp{}
+	if err := json.Unmarshal(body, pbp); err != nil {
+		return nil, fmt.Errorf("could not unmarshal play by play reply: %w", err)
+	}
+	return pbp, nil
+}
+
+func (c *Client) GetNflWeeklyScheduleRaw(ctx context.Context, year int, seasonType string, week int) ([]byte, error) {
+	url := fmt.Sprintf("%s/games/%d/%s/%d/schedule.json", c.nfl.baseURL(), year, seasonType, week)
+	body, err := c.get(ctx, c.nfl, "weekly-schedule", ratelimit.Schedule, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not get %s week %d schedule for year %d: %w", seasonType, week, year, err)
+	}
+	return body, nil
+}
+
+// GetNflWeeklySchedule returns the games of a single week of a season, e.g. to refresh the current week only
+func (c *Client) GetNflWeeklySchedule(ctx context.Context, year int, seasonType string, week int) (*NflWeeklySchedule, error) {
+	body, err := c.GetNflWeeklyScheduleRaw(ctx, year, seasonType, week)
+	if err != nil {
+		return nil, err
+	}
+	schedule := &NflWeeklySchedule{}
+	if err := json.Unmarshal(body, schedule); err != nil {
+		return nil, fmt.Errorf("could not unmarshal weekly schedule reply: %w", err)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,137 @@
+package sportsradar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"gamedl/lib/web/clients/retry"
+	"gamedl/lib/web/clients/usage"
+)
+
+// Rotation tells how the key of a request is picked among the api keys of a competition
+type Rotation string
+
+const (
+	// RoundRobin spreads the requests over the keys in turn
+	RoundRobin Rotation = "round-robin"
+	// Failover sends the requests with the first key until it is exhausted, then with the next one
+	Failover Rotation = "failover"
+)
+
+// Rotations are the rotations a client supports
+var Rotations = []Rotation{RoundRobin, Failover}
+
+// KeyStats is the usage of an api key during the life of a client
+type KeyStats struct {
+	Competition string
+	// KeyID is the fingerprint of the key, see usage.KeyID
+	KeyID string
+	// Requests counts the requests sent with the key, retries excluded
+	Requests int
+	// Exhausted is set once the key was refused for its quota, Reason telling how
+	Exhausted bool
+	Reason    string
+}
+
+type apiKey struct {
+	key string
+	KeyStats
+}
+
+// keyRing holds the api keys of a competition, and the keys marked exhausted for the life of the client.
+// It is safe for concurrent use.
+type keyRing struct {
+	m           sync.Mutex
+	competition string
+	keys        []*apiKey
+	next        int
+}
+
+func newKeyRing(competition string) *keyRing {
+	return &keyRing{competition: competition}
+}
+
+// add adds keys to the ring, skipping empty and duplicate ones
+func (r *keyRing) add(keys ...string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	for _, key := range keys {
+		if key == "" || slices.ContainsFunc(r.keys, func(k *apiKey) bool { return k.key == key }) {
+			continue
+		}
+		r.keys = append(r.keys, &apiKey{key: key, KeyStats: KeyStats{Competition: r.competition, KeyID: usage.KeyID(key)}})
+	}
+}
+
+// pick returns the key of the next request, or an error wrapping usage.ErrKeysExhausted when none is usable
+func (r *keyRing) pick(rotation Rotation) (*apiKey, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if len(r.keys) == 0 {
+		// Sent without a key, so the provider reports what's wrong
+		return &apiKey{}, nil
+	}
+	for i := range r.keys {
+		index := i
+		if rotation != Failover {
+			index = (r.next + i) % len(r.keys)
+		}
+		if k := r.keys[index]; !k.Exhausted {
+			r.next = index + 1
+			k.Requests++
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", r.competition, usage.ErrKeysExhausted)
+}
+
+// exhaust marks a key as exhausted when err tells its quota is used up, and reports whether
+// the request can be sent again with another key. A single key is never marked: with no key
+// to fail over to, its errors are returned as they are.
+func (r *keyRing) exhaust(k *apiKey, err error) bool {
+	reason, ok := quotaError(err)
+	if !ok {
+		return false
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	if len(r.keys) < 2 {
+		return false
+	}
+	if !k.Exhausted {
+		k.Exhausted = true
+		k.Reason = reason
+	}
+	return true
+}
+
+// quotaError tells whether err means the key of the request ran out of quota, or can't be used
+// at the access level of the request, and why. A 429 status code isn't one: SportRadar throttles
+// the requests sent too fast with it, which waiting fixes, so disabling the key would be wrong.
+func quotaError(err error) (string, bool) {
+	var statusErr *retry.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden {
+		return fmt.Sprintf("status code %d", statusErr.StatusCode), true
+	}
+	var budgetErr *usage.BudgetError
+	if errors.As(err, &budgetErr) && budgetErr.Key {
+		return "monthly budget exceeded", true
+	}
+	return "", false
+}
+
+// stats returns the usage of the keys of the ring
+func (r *keyRing) stats() []KeyStats {
+	r.m.Lock()
+	defer r.m.Unlock()
+	stats := make([]KeyStats, 0, len(r.keys))
+	for _, k := range r.keys {
+		stats = append(stats, k.KeyStats)
+	}
+	return stats
+}
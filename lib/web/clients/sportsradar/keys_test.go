@@ -0,0 +1,207 @@
+package sportsradar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gamedl/lib/web/clients/retry"
+	"gamedl/lib/web/clients/usage"
+)
+
+// keyServer answers the requests sent with a refused api key with its status code,
+// and the other ones with 200, recording the key of every request
+type keyServer struct {
+	*httptest.Server
+	m       sync.Mutex
+	refused map[string]int
+	keys    []string
+}
+
+func newKeyServer(t *testing.T, refused map[string]int) *keyServer {
+	t.Helper()
+	s := &keyServer{refused: refused}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-Api-Key")
+		s.m.Lock()
+		s.keys = append(s.keys, key)
+		s.m.Unlock()
+		if status, ok := s.refused[key]; ok {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"seasons":[]}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// requestKeys returns the keys of the requests received so far
+func (s *keyServer) requestKeys() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]string(nil), s.keys...)
+}
+
+func newKeyTestClient(server *keyServer, options ...ClientOption) *Client {
+	policy := retry.DefaultPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = time.Millisecond
+	return NewClient(append([]ClientOption{WithBaseURL(server.URL), WithRetryPolicy(policy)}, options...)...)
+}
+
+func equalKeys(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestKeyFailoverOnQuotaRefusal(t *testing.T) {
+	server := newKeyServer(t, map[string]int{"first": http.StatusForbidden})
+	client := newKeyTestClient(server, WithNbaAPIKeys("first", "second"), WithKeyRotation(Failover))
+
+	for range 2 {
+		if _, err := client.GetNbaSeasonsRaw(context.Background()); err != nil {
+			t.Fatalf("GetNbaSeasonsRaw returned an error: %v", err)
+		}
+	}
+
+	// The refused key is skipped for good
+	want := []string{"first", "second", "second"}
+	if got := server.requestKeys(); !equalKeys(got, want) {
+		t.Errorf("requests sent with keys %v, want %v", got, want)
+	}
+
+	stats := client.KeyStats()
+	if len(stats) != 2 || !stats[0].Exhausted || stats[1].Exhausted {
+		t.Fatalf("key stats = %+v, want the first key exhausted only", stats)
+	}
+	if stats[0].KeyID != usage.KeyID("first") || stats[0].Requests != 1 || stats[1].Requests != 2 {
+		t.Errorf("key stats = %+v, want 1 request with the first key and 2 with the second", stats)
+	}
+}
+
+func TestKeyNotExhaustedByThrottling(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"too many requests", http.StatusTooManyRequests},
+		{"unauthorized", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newKeyServer(t, map[string]int{"first": tt.status})
+			client := newKeyTestClient(server, WithNbaAPIKeys("first", "second"), WithKeyRotation(Failover))
+
+			_, err := client.GetNbaSeasonsRaw(context.Background())
+			var statusErr *retry.StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+				t.Fatalf("GetNbaSeasonsRaw returned %v, want a %d StatusError", err, tt.status)
+			}
+			for _, key := range server.requestKeys() {
+				if key != "first" {
+					t.Errorf("request sent with key %s, want the first key only", key)
+				}
+			}
+			if stats := client.KeyStats(); stats[0].Exhausted {
+				t.Errorf("key stats = %+v, want no key exhausted", stats)
+			}
+		})
+	}
+}
+
+func TestKeyRoundRobinSkipsExhaustedKeys(t *testing.T) {
+	server := newKeyServer(t, map[string]int{"b": http.StatusForbidden})
+	client := newKeyTestClient(server, WithNbaAPIKeys("a", "b", "c"))
+
+	for range 4 {
+		if _, err := client.GetNbaSeasonsRaw(context.Background()); err != nil {
+			t.Fatalf("GetNbaSeasonsRaw returned an error: %v", err)
+		}
+	}
+
+	want := []string{"a", "b", "c", "a", "c"}
+	if got := server.requestKeys(); !equalKeys(got, want) {
+		t.Errorf("requests sent with keys %v, want %v", got, want)
+	}
+}
+
+func TestKeysExhausted(t *testing.T) {
+	server := newKeyServer(t, map[string]int{"first": http.StatusForbidden, "second": http.StatusForbidden})
+	client := newKeyTestClient(server, WithNbaAPIKeys("first", "second"))
+
+	_, err := client.GetNbaSeasonsRaw(context.Background())
+	if !errors.Is(err, usage.ErrKeysExhausted) {
+		t.Fatalf("GetNbaSeasonsRaw returned %v, want an error wrapping usage.ErrKeysExhausted", err)
+	}
+	var statusErr *retry.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Errorf("GetNbaSeasonsRaw returned %v, want it to wrap the last refusal", err)
+	}
+
+	// Exhausted keys aren't tried again for the life of the client
+	if _, err := client.GetNbaSeasonsRaw(context.Background()); !errors.Is(err, usage.ErrKeysExhausted) {
+		t.Errorf("second GetNbaSeasonsRaw returned %v, want an error wrapping usage.ErrKeysExhausted", err)
+	}
+	if got := server.requestKeys(); !equalKeys(got, []string{"first", "second"}) {
+		t.Errorf("requests sent with keys %v, want [first second]", got)
+	}
+}
+
+func TestSingleKeyIsNeverExhausted(t *testing.T) {
+	server := newKeyServer(t, map[string]int{"only": http.StatusForbidden})
+	client := newKeyTestClient(server, WithNbaAPIKeys("only"))
+
+	for range 2 {
+		_, err := client.GetNbaSeasonsRaw(context.Background())
+		var statusErr *retry.StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+			t.Fatalf("GetNbaSeasonsRaw returned %v, want a 403 StatusError", err)
+		}
+	}
+	if stats := client.KeyStats(); len(stats) != 1 || stats[0].Exhausted {
+		t.Errorf("key stats = %+v, want the key not exhausted", stats)
+	}
+}
+
+func TestAPIKeyStaysOutOfURLs(t *testing.T) {
+	server := newKeyServer(t, nil)
+	var query string
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"seasons":[]}`))
+	})
+	client := newKeyTestClient(server, WithNbaAPIKeys("secret-key"))
+	if _, err := client.GetNbaSeasonsRaw(context.Background()); err != nil {
+		t.Fatalf("GetNbaSeasonsRaw returned an error: %v", err)
+	}
+	if query != "" {
+		t.Errorf("request sent with query %q, want the key in a header only", query)
+	}
+
+	// Errors of net/http quote the url of the request, and end up in the run reports
+	server.Close()
+	policy := retry.DefaultPolicy()
+	policy.MaxAttempts = 1
+	client = NewClient(WithBaseURL(server.URL), WithRetryPolicy(policy), WithNbaAPIKeys("secret-key"))
+	_, err := client.GetNbaSeasonsRaw(context.Background())
+	if err == nil {
+		t.Fatal("GetNbaSeasonsRaw returned no error from a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("error %q holds the api key", err)
+	}
+}
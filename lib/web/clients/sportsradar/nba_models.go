@@ -33,6 +33,18 @@ func (si *NBASeasonsInfo) FilterYears(years []int) {
 	si.Seasons = filteredSeasons
 }
 
+// FilterSeasonTypes keeps only the seasons whose type code is one of seasonTypes (e.g. PRE, REG, PST)
+func (si *NBASeasonsInfo) FilterSeasonTypes(seasonTypes []string) {
+	filteredSeasons := make([]*NBASeasonInfo, 0, len(si.Seasons))
+
+	for _, season := range si.Seasons {
+		if slices.Contains(seasonTypes, season.Type.Code) {
+			filteredSeasons = append(filteredSeasons, season)
+		}
+	}
+	si.Seasons = filteredSeasons
+}
+
 func (si *NBASeasonsInfo) FilterSeasonType(seasonType string) {
 	filteredSeasons := make([]*NBASeasonInfo, 0, len(si.Seasons))
 
@@ -125,6 +137,20 @@ type NBAGame struct {
 	Title              string `json:"title,omitempty"`
 }
 
+// TeamIdentifiers returns the names, aliases and ids of both teams of the game
+func (g *NBAGame) TeamIdentifiers() []string {
+	return []string{
+		g.Home.Name,
+		g.Home.Alias,
+		g.Home.Id,
+		g.Home.SrId,
+		g.Away.Name,
+		g.Away.Alias,
+		g.Away.Id,
+		g.Away.SrId,
+	}
+}
+
 type NbaGamePbp struct {
 	ID           string    `json:"id"`
 	Status       string    `json:"status"`
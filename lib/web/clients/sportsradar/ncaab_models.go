@@ -44,6 +44,18 @@ func (si *NcaabSeasonsInfo) FilterYears(years []int) {
 	si.Seasons = filteredSeasons
 }
 
+// FilterSeasonTypes keeps only the seasons whose type code is one of seasonTypes (e.g. PRE, REG, PST)
+func (si *NcaabSeasonsInfo) FilterSeasonTypes(seasonTypes []string) {
+	filteredSeasons := make([]*NcaabSeasonInfo, 0, len(si.Seasons))
+
+	for _, season := range si.Seasons {
+		if slices.Contains(seasonTypes, season.Type.Code) {
+			filteredSeasons = append(filteredSeasons, season)
+		}
+	}
+	si.Seasons = filteredSeasons
+}
+
 type NcaabSeasonSchedule struct {
 	League struct {
 		ID    string `json:"id"`
@@ -103,6 +115,18 @@ type NcaabGame struct {
 	Title        string `json:"title,omitempty"`
 }
 
+// TeamIdentifiers returns the names, aliases and ids of both teams of the game
+func (g *NcaabGame) TeamIdentifiers() []string {
+	return []string{
+		g.Home.Name,
+		g.Home.Alias,
+		g.Home.ID,
+		g.Away.Name,
+		g.Away.Alias,
+		g.Away.ID,
+	}
+}
+
 type NcaabGamePbp struct {
 	ID             string    `json:"id"`
 	Status         string    `json:"status"`
@@ -43,6 +43,18 @@ func (si *NcaafSeasonsInfo) FilterYears(years []int) {
 	si.Seasons = filteredSeasons
 }
 
+// FilterSeasonTypes keeps only the seasons whose type code is one of seasonTypes (e.g. PRE, REG, PST)
+func (si *NcaafSeasonsInfo) FilterSeasonTypes(seasonTypes []string) {
+	filteredSeasons := make([]*NcaafSeasonInfo, 0, len(si.Seasons))
+
+	for _, season := range si.Seasons {
+		if slices.Contains(seasonTypes, season.Type.Code) {
+			filteredSeasons = append(filteredSeasons, season)
+		}
+	}
+	si.Seasons = filteredSeasons
+}
+
 type NcaafSeasonSchedule struct {
 	ID    string `json:"id"`
 	Year  int    `json:"year"`
@@ -132,6 +144,18 @@ type NcaafGame struct {
 	} `json:"scoring"`
 }
 
+// TeamIdentifiers returns the names, aliases and ids of both teams of the game
+func (g *NcaafGame) TeamIdentifiers() []string {
+	return []string{
+		g.Home.Name,
+		g.Home.Alias,
+		g.Home.ID,
+		g.Away.Name,
+		g.Away.Alias,
+		g.Away.ID,
+	}
+}
+
 type NcaafGamePbp struct {
 	ID             string    `json:"id"`
 	Status         string    `json:"status"`
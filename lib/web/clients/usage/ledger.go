@@ -0,0 +1,274 @@
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Layouts of the day and month keys of the ledger
+const (
+	DayLayout   = "2006-01-02"
+	MonthLayout = "2006-01"
+)
+
+// Budgets limits the calls a Ledger allows
+type Budgets struct {
+	// MaxCalls is the number of calls a run may make, unlimited when 0
+	MaxCalls int
+	// Monthly is the number of calls each key of a credential may make in a month, keyed by
+	// credential (e.g. sportradar/nba). Credentials missing or set to 0 are unlimited.
+	Monthly map[string]int
+}
+
+// KeyUsage counts the calls made with a key of a credential, by endpoint
+type KeyUsage struct {
+	Credential string `json:"credential"`
+	KeyID      string `json:"key_id"`
+	// Days and Months are keyed by DayLayout and MonthLayout dates, then by endpoint
+	Days   map[string]map[string]int `json:"days"`
+	Months map[string]map[string]int `json:"months"`
+}
+
+func newKeyUsage(credential, keyID string) *KeyUsage {
+	return &KeyUsage{
+		Credential: credential,
+		KeyID:      keyID,
+		Days:       make(map[string]map[string]int),
+		Months:     make(map[string]map[string]int),
+	}
+}
+
+// add counts calls to an endpoint made at the given day and month
+func (k *KeyUsage) add(day, month, endpoint string, calls int) {
+	if k.Days[day] == nil {
+		k.Days[day] = make(map[string]int)
+	}
+	k.Days[day][endpoint] += calls
+	if k.Months[month] == nil {
+		k.Months[month] = make(map[string]int)
+	}
+	k.Months[month][endpoint] += calls
+}
+
+// Month returns the calls of a month by endpoint
+func (k *KeyUsage) Month(month string) map[string]int {
+	return k.Months[month]
+}
+
+// Total returns the calls of a month or day, every endpoint included
+func Total(calls map[string]int) int {
+	total := 0
+	for _, n := range calls {
+		total += n
+	}
+	return total
+}
+
+// Ledger is a Recorder persisting the calls made with each key to a local file, and refusing
+// the calls that would exceed its budgets. It is safe for concurrent use.
+type Ledger struct {
+	m       sync.Mutex
+	path    string
+	budgets Budgets
+	now     func() time.Time
+	// keys holds the calls saved in the file when loaded plus the ones of this run,
+	// run only the ones of this run, which Save adds to the file
+	keys     map[string]*KeyUsage
+	run      map[string]*KeyUsage
+	runCalls int
+}
+
+type ledgerFile struct {
+	Keys []*KeyUsage `json:"keys"`
+}
+
+// LoadLedger reads the ledger saved at path, returning an empty ledger if none exists yet
+func LoadLedger(path string, budgets Budgets) (*Ledger, error) {
+	keys, err := readLedgerFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Ledger{
+		path:    path,
+		budgets: budgets,
+		now:     time.Now,
+		keys:    keys,
+		run:     make(map[string]*KeyUsage),
+	}, nil
+}
+
+func readLedgerFile(path string) (map[string]*KeyUsage, error) {
+	keys := make(map[string]*KeyUsage)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading usage ledger %s: %w", path, err)
+	}
+
+	file := ledgerFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unmarshaling usage ledger %s: %w", path, err)
+	}
+	for _, k := range file.Keys {
+		usage := newKeyUsage(k.Credential, k.KeyID)
+		for day, calls := range k.Days {
+			usage.Days[day] = calls
+		}
+		for month, calls := range k.Months {
+			usage.Months[month] = calls
+		}
+		keys[ledgerKey(k.Credential, k.KeyID)] = usage
+	}
+	return keys, nil
+}
+
+func ledgerKey(credential, keyID string) string {
+	return credential + "#" + keyID
+}
+
+// Use counts a call, unless it would exceed the calls allowed to the run or the monthly budget of its key
+func (l *Ledger) Use(call Call) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	now := l.now()
+	day, month := now.Format(DayLayout), now.Format(MonthLayout)
+	key := ledgerKey(call.Credential, call.KeyID)
+
+	if l.budgets.MaxCalls > 0 && l.runCalls >= l.budgets.MaxCalls {
+		return &BudgetError{Reason: fmt.Sprintf("the run already made its %d calls", l.budgets.MaxCalls)}
+	}
+	if budget := l.budgets.Monthly[call.Credential]; budget > 0 {
+		if used := l.monthCalls(key, month); used >= budget {
+			return &BudgetError{Key: true, Reason: fmt.Sprintf("%s key %s made %d of its %d calls of %s", call.Credential, call.KeyID, used, budget, month)}
+		}
+	}
+
+	for _, keys := range []map[string]*KeyUsage{l.keys, l.run} {
+		if keys[key] == nil {
+			keys[key] = newKeyUsage(call.Credential, call.KeyID)
+		}
+		keys[key].add(day, month, call.Endpoint, 1)
+	}
+	l.runCalls++
+	return nil
+}
+
+func (l *Ledger) monthCalls(key, month string) int {
+	if k, ok := l.keys[key]; ok {
+		return Total(k.Month(month))
+	}
+	return 0
+}
+
+// RunCalls returns the number of calls counted since the ledger was loaded
+func (l *Ledger) RunCalls() int {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.runCalls
+}
+
+// Keys returns the usage of every key, sorted by credential then key id
+func (l *Ledger) Keys() []*KeyUsage {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return sortedKeys(l.keys)
+}
+
+func sortedKeys(keys map[string]*KeyUsage) []*KeyUsage {
+	sorted := make([]*KeyUsage, 0, len(keys))
+	for _, k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Credential != sorted[j].Credential {
+			return sorted[i].Credential < sorted[j].Credential
+		}
+		return sorted[i].KeyID < sorted[j].KeyID
+	})
+	return sorted
+}
+
+// Save adds the calls of this run to the ledger file. The file is read again first,
+// so runs sharing the ledger don't lose each other's calls.
+func (l *Ledger) Save() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if len(l.run) == 0 {
+		return nil
+	}
+
+	keys, err := readLedgerFile(l.path)
+	if err != nil {
+		return err
+	}
+	for key, run := range l.run {
+		if keys[key] == nil {
+			keys[key] = newKeyUsage(run.Credential, run.KeyID)
+		}
+		for day, calls := range run.Days {
+			for endpoint, n := range calls {
+				keys[key].Days[day] = addCalls(keys[key].Days[day], endpoint, n)
+			}
+		}
+		for month, calls := range run.Months {
+			for endpoint, n := range calls {
+				keys[key].Months[month] = addCalls(keys[key].Months[month], endpoint, n)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(ledgerFile{Keys: sortedKeys(keys)}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling usage ledger: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("creating usage ledger directory: %w", err)
+	}
+	if err := writeFileAtomic(l.path, data); err != nil {
+		return fmt.Errorf("writing usage ledger %s: %w", l.path, err)
+	}
+
+	// The calls of this run are in the file now
+	l.keys = keys
+	l.run = make(map[string]*KeyUsage)
+	return nil
+}
+
+func addCalls(calls map[string]int, endpoint string, n int) map[string]int {
+	if calls == nil {
+		calls = make(map[string]int)
+	}
+	calls[endpoint] += n
+	return calls
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+	}
+	return err
+}
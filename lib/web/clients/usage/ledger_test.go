@@ -0,0 +1,170 @@
+package usage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gamedl/lib/web/clients/retry"
+)
+
+func TestLedgerBudgets(t *testing.T) {
+	nba := Call{Credential: "sportradar/nba", KeyID: "aaaa", Endpoint: "pbp"}
+	otherKey := Call{Credential: "sportradar/nba", KeyID: "bbbb", Endpoint: "pbp"}
+	nfl := Call{Credential: "sportradar/nfl", KeyID: "cccc", Endpoint: "pbp"}
+
+	tests := []struct {
+		name    string
+		budgets Budgets
+		// saved are the calls saved in the ledger file by a previous run
+		saved   []Call
+		calls   []Call
+		refused []bool
+		keyErr  []bool
+	}{
+		{
+			name:    "unlimited",
+			calls:   []Call{nba, nba, nba},
+			refused: []bool{false, false, false},
+		},
+		{
+			name:    "run budget",
+			budgets: Budgets{MaxCalls: 2},
+			calls:   []Call{nba, nfl, otherKey},
+			refused: []bool{false, false, true},
+			keyErr:  []bool{false, false, false},
+		},
+		{
+			name:    "monthly budget of each key",
+			budgets: Budgets{Monthly: map[string]int{"sportradar/nba": 2}},
+			calls:   []Call{nba, nba, nba, otherKey, nfl},
+			refused: []bool{false, false, true, false, false},
+			keyErr:  []bool{false, false, true, false, false},
+		},
+		{
+			name:    "monthly budget counts the saved calls",
+			budgets: Budgets{Monthly: map[string]int{"sportradar/nba": 2}},
+			saved:   []Call{nba},
+			calls:   []Call{nba, nba},
+			refused: []bool{false, true},
+			keyErr:  []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "usage.json")
+			if len(tt.saved) > 0 {
+				previous, err := LoadLedger(path, Budgets{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, call := range tt.saved {
+					if err := previous.Use(call); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := previous.Save(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ledger, err := LoadLedger(path, tt.budgets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			allowed := 0
+			for i, call := range tt.calls {
+				err := ledger.Use(call)
+				if refused := err != nil; refused != tt.refused[i] {
+					t.Fatalf("call %d: Use returned %v, want refused %v", i, err, tt.refused[i])
+				}
+				if err == nil {
+					allowed++
+					continue
+				}
+				var budgetErr *BudgetError
+				if !errors.As(err, &budgetErr) || budgetErr.Key != tt.keyErr[i] {
+					t.Errorf("call %d: Use returned %v, want a BudgetError with Key %v", i, err, tt.keyErr[i])
+				}
+				if !errors.Is(err, ErrBudgetExceeded) || !errors.Is(err, retry.ErrPermanent) || !OutOfCalls(err) {
+					t.Errorf("call %d: Use returned %v, want it to match ErrBudgetExceeded and retry.ErrPermanent", i, err)
+				}
+			}
+			if got := ledger.RunCalls(); got != allowed {
+				t.Errorf("RunCalls() = %d, want the %d calls allowed", got, allowed)
+			}
+		})
+	}
+}
+
+func TestLedgerMonthlyBudgetResetsEveryMonth(t *testing.T) {
+	ledger, err := LoadLedger(filepath.Join(t.TempDir(), "usage.json"), Budgets{Monthly: map[string]int{"betgenius/stats": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := Call{Credential: "betgenius/stats", KeyID: "aaaa", Endpoint: "nfl/pbp"}
+
+	ledger.now = func() time.Time { return time.Date(2024, 10, 31, 23, 0, 0, 0, time.UTC) }
+	if err := ledger.Use(call); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.Use(call); err == nil {
+		t.Error("second call of October allowed over a budget of 1")
+	}
+	ledger.now = func() time.Time { return time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC) }
+	if err := ledger.Use(call); err != nil {
+		t.Errorf("first call of November refused: %v", err)
+	}
+}
+
+func TestLedgerSaveMergesConcurrentRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	call := Call{Credential: "sportradar/nba", KeyID: "aaaa", Endpoint: "pbp"}
+	now := func() time.Time { return time.Date(2024, 11, 18, 12, 0, 0, 0, time.UTC) }
+
+	// Both runs load the ledger before the other one saves its calls
+	first, err := LoadLedger(path, Budgets{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LoadLedger(path, Budgets{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.now, second.now = now, now
+	for range 2 {
+		first.Use(call)
+	}
+	for range 3 {
+		second.Use(call)
+	}
+	second.Use(Call{Credential: "sportradar/nba", KeyID: "aaaa", Endpoint: "schedule"})
+
+	for _, ledger := range []*Ledger{first, second} {
+		if err := ledger.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Saving again without new calls doesn't count them twice
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := LoadLedger(path, Budgets{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := saved.Keys()
+	if len(keys) != 1 {
+		t.Fatalf("ledger holds %d keys, want 1", len(keys))
+	}
+	month := keys[0].Month("2024-11")
+	if month["pbp"] != 5 || month["schedule"] != 1 {
+		t.Errorf("calls of the month = %v, want 5 pbp and 1 schedule", month)
+	}
+	if day := keys[0].Days["2024-11-18"]; Total(day) != 6 {
+		t.Errorf("calls of the day = %v, want 6", day)
+	}
+}
@@ -0,0 +1,103 @@
+package usage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gamedl/lib/web/clients/retry"
+)
+
+// ErrBudgetExceeded is returned, wrapped in a BudgetError, for the requests a Recorder refuses
+// because they would exceed a call budget
+var ErrBudgetExceeded = errors.New("call budget exceeded")
+
+// ErrKeysExhausted is returned, wrapped, by clients once every key of a credential ran out of quota
+var ErrKeysExhausted = errors.New("all api keys exhausted")
+
+// OutOfCalls reports whether err means the calls left are used up: the request was refused by a
+// budget, or every key of its credential is exhausted. Clients rotating keys only return the
+// refusal of a key budget once no other key can be used.
+func OutOfCalls(err error) bool {
+	return errors.Is(err, ErrBudgetExceeded) || errors.Is(err, ErrKeysExhausted)
+}
+
+// BudgetError is returned for a request refused by a call budget. It matches ErrBudgetExceeded,
+// and retry.ErrPermanent so the request isn't retried.
+type BudgetError struct {
+	// Key is set when the budget is the one of the key of the request, so other keys may still be used
+	Key    bool
+	Reason string
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrBudgetExceeded, e.Reason)
+}
+
+func (e *BudgetError) Is(target error) bool {
+	return target == ErrBudgetExceeded || target == retry.ErrPermanent
+}
+
+// Call identifies a request counted against the quota of a credential
+type Call struct {
+	// Credential names the credential the request is made with, e.g. sportradar/nba
+	Credential string
+	// KeyID tells apart the keys of a credential without revealing them, see KeyID
+	KeyID string
+	// Endpoint names the endpoint of the request, e.g. pbp
+	Endpoint string
+}
+
+// KeyID returns a short fingerprint of a secret, safe to store and display
+func KeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Recorder counts the requests sent with a credential
+type Recorder interface {
+	// Use records a request about to be sent, or refuses it with a BudgetError
+	Use(call Call) error
+}
+
+type callKey struct{}
+
+// WithCall returns a context tagging the requests made with it as calls to count
+func WithCall(ctx context.Context, call Call) context.Context {
+	return context.WithValue(ctx, callKey{}, call)
+}
+
+// CallFromContext returns the call set with WithCall
+func CallFromContext(ctx context.Context) (Call, bool) {
+	call, ok := ctx.Value(callKey{}).(Call)
+	return call, ok
+}
+
+// Transport is an http.RoundTripper that records every request tagged with WithCall before
+// sending it, retries included. Requests without a call go through uncounted.
+type Transport struct {
+	Base     http.RoundTripper
+	Recorder Recorder
+}
+
+// NewTransport wraps base (http.DefaultTransport when nil) so its requests are recorded
+func NewTransport(base http.RoundTripper, recorder Recorder) *Transport {
+	return &Transport{Base: base, Recorder: recorder}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if call, ok := CallFromContext(req.Context()); ok && t.Recorder != nil {
+		if err := t.Recorder.Use(call); err != nil {
+			return nil, err
+		}
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}